@@ -1,6 +1,9 @@
 package dsl
 
-import "github.com/lestrrat/go-graphql/model"
+import (
+	"github.com/lestrrat/go-graphql/model"
+	"golang.org/x/net/context"
+)
 
 type Attribute interface {
 }
@@ -19,8 +22,14 @@ func Name(s string) Attribute {
 	return nameAttr{stringAttr(s)}
 }
 
+type descriptionAttr struct {
+	stringAttr
+}
+
+// Description creates an attribute that sets the description of
+// a type, field, argument or enum value
 func Description(s string) Attribute {
-	return stringAttr(s)
+	return descriptionAttr{stringAttr(s)}
 }
 
 type ObjectDefinition struct {
@@ -38,7 +47,9 @@ func (v ObjectDefinition) Configure(attrs ...Attribute) ObjectDefinition {
 		case ObjectBlock:
 			attr.(ObjectBlock).Call(v)
 		case ImplementsDefinition:
-			v.typ.SetImplements(attr.(ImplementsDefinition).typ)
+			v.typ.AddInterfaces(attr.(ImplementsDefinition).types...)
+		case descriptionAttr:
+			v.typ.SetDescription(attr.(descriptionAttr).Value().(string))
 		case model.ObjectFieldDefinition:
 			fields.Add(attr.(model.ObjectFieldDefinition))
 		}
@@ -63,15 +74,18 @@ func (v InterfaceDefinition) Type() model.InterfaceDefinition {
 }
 
 type ImplementsDefinition struct {
-	typ model.NamedType
+	types []model.NamedType
 }
 
-func Implements(t model.NamedType) ImplementsDefinition {
-	return ImplementsDefinition{typ: t}
+// Implements specifies the interfaces implemented by an object type
+// or by an interface
+func Implements(types ...model.NamedType) ImplementsDefinition {
+	return ImplementsDefinition{types: types}
 }
 
-func (def ImplementsDefinition) Type() model.Type {
-	return def.typ
+// Types returns the interfaces that are implemented
+func (def ImplementsDefinition) Types() []model.NamedType {
+	return def.types
 }
 
 func (v InterfaceDefinition) Configure(attrs ...Attribute) InterfaceDefinition {
@@ -80,6 +94,12 @@ func (v InterfaceDefinition) Configure(attrs ...Attribute) InterfaceDefinition {
 		switch attr.(type) {
 		case InterfaceBlock:
 			attr.(InterfaceBlock).Call(v)
+		case ImplementsDefinition:
+			v.typ.AddInterfaces(attr.(ImplementsDefinition).types...)
+		case model.Resolver:
+			v.typ.SetTypeResolver(attr.(model.Resolver))
+		case descriptionAttr:
+			v.typ.SetDescription(attr.(descriptionAttr).Value().(string))
 		case model.InterfaceFieldDefinition:
 			fields.Add(attr.(model.InterfaceFieldDefinition))
 		}
@@ -98,6 +118,7 @@ func (f InterfaceBlock) Call(v InterfaceDefinition) {
 type ObjectFieldDefinition struct {
 	field model.ObjectFieldDefinition
 }
+
 func (def ObjectFieldDefinition) Field() model.ObjectFieldDefinition {
 	return def.field
 }
@@ -108,8 +129,24 @@ func (v *ObjectFieldDefinition) Configure(attrs ...Attribute) *ObjectFieldDefini
 		switch attr.(type) {
 		case model.ObjectFieldArgumentDefinition:
 			arguments.Add(attr.(model.ObjectFieldArgumentDefinition))
+		case model.FieldResolver:
+			v.field.SetResolver(attr.(model.FieldResolver))
+		case descriptionAttr:
+			v.field.SetDescription(attr.(descriptionAttr).Value().(string))
 		}
 	}
 	v.field.AddArguments(arguments...)
 	return v
 }
+
+// FieldResolver creates an attribute that sets the resolver used to
+// produce the value of an object field
+func FieldResolver(f func(context.Context, interface{}, map[string]interface{}) (interface{}, error)) model.FieldResolver {
+	return model.FieldResolverFunc(f)
+}
+
+// TypeResolver creates an attribute that sets the resolver used to
+// determine the concrete type of a value of an interface type
+func TypeResolver(f func(interface{}) model.Type) model.Resolver {
+	return model.ResolverFunc(f)
+}
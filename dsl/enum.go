@@ -3,22 +3,32 @@ package dsl
 import "github.com/lestrrat/go-graphql/model"
 
 func EnumValue(name string, value model.Value, attrs ...Attribute) model.EnumElementDefinition {
-	return model.NewEnumElementDefinition(name, value)
+	def := model.NewEnumElementDefinition(name, value)
+	for _, attr := range attrs {
+		switch attr.(type) {
+		case descriptionAttr:
+			def.SetDescription(attr.(descriptionAttr).Value().(string))
+		}
+	}
+	return def
 }
 
 func Enum(attrs ...Attribute) model.EnumDefinition {
-	var name string
+	var name, description string
 	var elements model.EnumElementDefinitionList
 	for _, attr := range attrs {
 		switch attr.(type) {
 		case nameAttr:
 			name = attr.(nameAttr).Value().(string)
+		case descriptionAttr:
+			description = attr.(descriptionAttr).Value().(string)
 		case model.EnumElementDefinition:
 			elements.Add(attr.(model.EnumElementDefinition))
 		}
 	}
 
 	def := model.NewEnumDefinition(name)
+	def.SetDescription(description)
 	def.AddElements(elements...)
 	return def
 }
@@ -9,11 +9,70 @@ func NotNull(v model.Type) model.Type {
 	return v
 }
 
+// String creates a reference to the built-in String scalar
 func String() model.Type {
-	// This is wrong. just a place holder
 	return model.NewNamedType(`String`)
 }
 
+// Int creates a reference to the built-in Int scalar
+func Int() model.Type {
+	return model.NewNamedType(`Int`)
+}
+
+// Float creates a reference to the built-in Float scalar
+func Float() model.Type {
+	return model.NewNamedType(`Float`)
+}
+
+// Boolean creates a reference to the built-in Boolean scalar
+func Boolean() model.Type {
+	return model.NewNamedType(`Boolean`)
+}
+
+// ID creates a reference to the built-in ID scalar
+func ID() model.Type {
+	return model.NewNamedType(`ID`)
+}
+
+// Scalar creates a custom scalar type. The conversion of its values
+// can be customized with the Serialize, ParseValue and ParseLiteral
+// attributes
+func Scalar(name string, attrs ...Attribute) model.ScalarDefinition {
+	def := model.NewScalarDefinition(name)
+	for _, attr := range attrs {
+		switch attr.(type) {
+		case descriptionAttr:
+			def.SetDescription(attr.(descriptionAttr).Value().(string))
+		case model.SerializeFunc:
+			def.SetSerializeFunc(attr.(model.SerializeFunc))
+		case model.ParseValueFunc:
+			def.SetParseValueFunc(attr.(model.ParseValueFunc))
+		case model.ParseLiteralFunc:
+			def.SetParseLiteralFunc(attr.(model.ParseLiteralFunc))
+		}
+	}
+	return def
+}
+
+// Serialize creates an attribute that sets the function used to
+// convert resolved values of a custom scalar into their result
+// representation
+func Serialize(f func(interface{}) (interface{}, error)) model.SerializeFunc {
+	return model.SerializeFunc(f)
+}
+
+// ParseValue creates an attribute that sets the function used to
+// convert variable values of a custom scalar
+func ParseValue(f func(interface{}) (interface{}, error)) model.ParseValueFunc {
+	return model.ParseValueFunc(f)
+}
+
+// ParseLiteral creates an attribute that sets the function used to
+// convert literal values of a custom scalar
+func ParseLiteral(f func(model.Value) (interface{}, error)) model.ParseLiteralFunc {
+	return model.ParseLiteralFunc(f)
+}
+
 func List(t model.Type) model.ListType {
 	return model.NewListType(t)
 }
@@ -25,7 +84,16 @@ func Interface(name string, attrs ...Attribute) InterfaceDefinition {
 }
 
 func InterfaceField(name string, typ model.Type, attrs ...Attribute) model.InterfaceFieldDefinition {
-	return model.NewInterfaceFieldDefinition(name, typ)
+	def := model.NewInterfaceFieldDefinition(name, typ)
+	for _, attr := range attrs {
+		switch attr.(type) {
+		case model.ObjectFieldArgumentDefinition:
+			def.AddArguments(attr.(model.ObjectFieldArgumentDefinition))
+		case descriptionAttr:
+			def.SetDescription(attr.(descriptionAttr).Value().(string))
+		}
+	}
+	return def
 }
 
 func Object(name string, attrs ...Attribute) ObjectDefinition {
@@ -41,7 +109,14 @@ func ObjectField(name string, typ model.Type, attrs ...Attribute) model.ObjectFi
 }
 
 func ObjectFieldArgument(name string, typ model.Type, attrs ...Attribute) model.ObjectFieldArgumentDefinition {
-	return model.NewObjectFieldArgumentDefinition(name, typ)
+	def := model.NewObjectFieldArgumentDefinition(name, typ)
+	for _, attr := range attrs {
+		switch attr.(type) {
+		case descriptionAttr:
+			def.SetDescription(attr.(descriptionAttr).Value().(string))
+		}
+	}
+	return def
 }
 
 func NamedType(s string) model.NamedType {
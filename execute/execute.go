@@ -0,0 +1,522 @@
+// Package execute implements execution of GraphQL operations against
+// a schema document
+package execute
+
+import (
+	"reflect"
+
+	"github.com/lestrrat/go-graphql/introspection"
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// errPropagate is used to signal that a non-null field could not be
+// completed, and therefore the parent value must become null. The
+// actual error has already been recorded by the time this is returned
+var errPropagate = errors.New(`null propagated from non-null field`)
+
+type execCtx struct {
+	context.Context
+
+	schema        model.Document
+	doc           model.Document
+	operation     model.OperationDefinition
+	rootType      model.ObjectDefinition
+	variables     map[string]interface{}
+	errors        []*Error
+	introspection *introspector
+}
+
+// Execute runs the operation called `name` in `doc` against `schema`,
+// using `root` as the value passed to the resolvers of the root
+// fields. `name` may be empty if `doc` contains exactly one operation.
+//
+// The returned error is only non-nil when the request itself could not
+// be executed (e.g. unknown operation, bad variables). Errors raised
+// while resolving fields are reported in Result.Errors instead
+func Execute(c context.Context, schema, doc model.Document, name string, variables map[string]interface{}, root interface{}) (*Result, error) {
+	op, err := findOperation(doc, name)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to find operation`)
+	}
+
+	rootType, err := lookupRootType(schema, op.OperationType())
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to lookup root type`)
+	}
+
+	var ctx execCtx
+	ctx.Context = c
+	ctx.schema = schema
+	ctx.doc = doc
+	ctx.operation = op
+	ctx.rootType = rootType
+
+	if err := ctx.coerceVariableValues(op, variables); err != nil {
+		return nil, errors.Wrap(err, `failed to coerce variables`)
+	}
+
+	var res Result
+	data, err := ctx.executeSelectionSet(op.Selections(), rootType, root, nil)
+	if err == nil {
+		res.Data = data
+	}
+	res.Errors = ctx.errors
+	return &res, nil
+}
+
+func findOperation(doc model.Document, name string) (model.OperationDefinition, error) {
+	var found model.OperationDefinition
+	for def := range doc.Definitions() {
+		op, ok := def.(model.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			if found != nil {
+				return nil, errors.New(`operation name is required when document contains multiple operations`)
+			}
+			found = op
+			continue
+		}
+
+		if op.Name() == name {
+			return op, nil
+		}
+	}
+
+	if found == nil {
+		if name == "" {
+			return nil, errors.New(`no operations in document`)
+		}
+		return nil, errors.Errorf(`unknown operation "%s"`, name)
+	}
+	return found, nil
+}
+
+func lookupRootType(schema model.Document, typ model.OperationType) (model.ObjectDefinition, error) {
+	var name string
+	switch typ {
+	case model.OperationTypeQuery:
+		name = "Query"
+	case model.OperationTypeMutation:
+		name = "Mutation"
+	case model.OperationTypeSubscription:
+		name = "Subscription"
+	default:
+		return nil, errors.Errorf(`unsupported operation type %s`, typ)
+	}
+
+	if s, ok := schema.LookupSchema(); ok {
+		var t model.NamedType
+		switch typ {
+		case model.OperationTypeQuery:
+			t = s.Query()
+		case model.OperationTypeMutation:
+			t = s.Mutation()
+		case model.OperationTypeSubscription:
+			t = s.Subscription()
+		}
+		if t == nil {
+			return nil, errors.Errorf(`schema does not support %s operations`, typ)
+		}
+		name = t.Name()
+	}
+
+	def, ok := schema.LookupType(name)
+	if !ok {
+		return nil, errors.Errorf(`root type %s not found in schema`, name)
+	}
+
+	obj, ok := def.(model.ObjectDefinition)
+	if !ok {
+		return nil, errors.Errorf(`root type %s is not an object type`, name)
+	}
+	return obj, nil
+}
+
+func (ctx *execCtx) addError(err error, path []interface{}) {
+	ctx.errors = append(ctx.errors, &Error{
+		Message: err.Error(),
+		Path:    path,
+	})
+}
+
+// handleFieldError records `err` (unless it has already been recorded)
+// and decides if the null value should propagate to the parent
+func (ctx *execCtx) handleFieldError(err error, typ model.Type, path []interface{}) (interface{}, error) {
+	if err != errPropagate {
+		ctx.addError(err, path)
+	}
+
+	if model.IsNullable(typ) {
+		return nil, nil
+	}
+	return nil, errPropagate
+}
+
+func (ctx *execCtx) executeSelectionSet(selections chan model.Selection, objType model.ObjectDefinition, source interface{}, path []interface{}) (*OrderedMap, error) {
+	fields, err := ctx.collectFields(objType, selections, make(map[string]struct{}))
+	if err != nil {
+		ctx.addError(err, path)
+		return nil, errPropagate
+	}
+
+	result := NewOrderedMap()
+	for _, key := range fields.keys {
+		fieldPath := appendPath(path, key)
+		v, err := ctx.executeField(objType, source, fields.fields[key], fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		result.Set(key, v)
+	}
+	return result, nil
+}
+
+func (ctx *execCtx) executeField(objType model.ObjectDefinition, source interface{}, fields []model.SelectionField, path []interface{}) (interface{}, error) {
+	field := fields[0]
+	if field.Name() == "__typename" {
+		return objType.Name(), nil
+	}
+
+	fdef, ok := ctx.lookupField(objType, field.Name())
+	if !ok {
+		ctx.addError(errors.Errorf(`field "%s" is not defined on type %s`, field.Name(), objType.Name()), path)
+		return nil, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return ctx.handleFieldError(err, fdef.Type(), path)
+	}
+
+	args, err := ctx.coerceArgumentValues(fdef, field)
+	if err != nil {
+		return ctx.handleFieldError(err, fdef.Type(), path)
+	}
+
+	resolved, err := ctx.resolveField(fdef, source, args)
+	if err != nil {
+		return ctx.handleFieldError(err, fdef.Type(), path)
+	}
+
+	v, err := ctx.completeValue(fdef.Type(), fields, resolved, path)
+	if err != nil {
+		return ctx.handleFieldError(err, fdef.Type(), path)
+	}
+	return v, nil
+}
+
+func (ctx *execCtx) resolveField(fdef model.ObjectFieldDefinition, source interface{}, args map[string]interface{}) (interface{}, error) {
+	if r := fdef.Resolver(); r != nil {
+		return r.ResolveField(ctx, source, args)
+	}
+	return DefaultResolveField(ctx, fdef.Name(), source, args)
+}
+
+func (ctx *execCtx) completeValue(typ model.Type, fields []model.SelectionField, result interface{}, path []interface{}) (interface{}, error) {
+	if model.IsNullable(typ) {
+		if isNil(result) {
+			return nil, nil
+		}
+		return ctx.completeValueOfType(typ, fields, result, path)
+	}
+
+	if isNil(result) {
+		return nil, errors.Errorf(`cannot return null for non-nullable field %s`, fields[0].Name())
+	}
+
+	v, err := ctx.completeValueOfType(typ, fields, result, path)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, errors.Errorf(`cannot return null for non-nullable field %s`, fields[0].Name())
+	}
+	return v, nil
+}
+
+func (ctx *execCtx) completeValueOfType(typ model.Type, fields []model.SelectionField, result interface{}, path []interface{}) (interface{}, error) {
+	if lt, ok := typ.(model.ListType); ok {
+		return ctx.completeListValue(lt, fields, result, path)
+	}
+
+	name := model.TypeName(typ)
+	def, ok := ctx.lookupType(name)
+	if !ok {
+		// types that are referenced but never defined are treated
+		// as scalars that pass the value through
+		return result, nil
+	}
+
+	switch def.(type) {
+	case model.ScalarDefinition:
+		return def.(model.ScalarDefinition).Serialize(result)
+	case model.ObjectDefinition:
+		return ctx.completeObjectValue(def.(model.ObjectDefinition), fields, result, path)
+	case model.InterfaceDefinition, model.UnionDefinition:
+		obj, err := ctx.resolveAbstractType(def, result)
+		if err != nil {
+			return nil, err
+		}
+		return ctx.completeObjectValue(obj, fields, result, path)
+	case model.EnumDefinition:
+		return serializeEnum(def.(model.EnumDefinition), result)
+	default:
+		return nil, errors.Errorf(`type %s can not be used as an output type`, name)
+	}
+}
+
+func (ctx *execCtx) completeListValue(typ model.ListType, fields []model.SelectionField, result interface{}, path []interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(result)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+	default:
+		return nil, errors.Errorf(`expected a list value for field %s, got %T`, fields[0].Name(), result)
+	}
+
+	list := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		itemPath := appendPath(path, i)
+		v, err := ctx.completeValue(typ.Type(), fields, rv.Index(i).Interface(), itemPath)
+		if err != nil {
+			v, err = ctx.handleFieldError(err, typ.Type(), itemPath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+func (ctx *execCtx) completeObjectValue(obj model.ObjectDefinition, fields []model.SelectionField, result interface{}, path []interface{}) (interface{}, error) {
+	var selections model.SelectionList
+	for _, field := range fields {
+		for sel := range field.Selections() {
+			selections.Add(sel)
+		}
+	}
+
+	v, err := ctx.executeSelectionSet(selections.Iterator(), obj, result, path)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (ctx *execCtx) resolveAbstractType(def model.Definition, value interface{}) (model.ObjectDefinition, error) {
+	var r model.Resolver
+	if trc, ok := def.(model.TypeResolverContainer); ok {
+		r = trc.TypeResolver()
+	}
+	if r == nil {
+		return nil, errors.Errorf(`abstract type %s does not have a type resolver`, def.Name())
+	}
+
+	name := model.TypeName(r.Resolve(value))
+	if name == "" {
+		return nil, errors.Errorf(`failed to resolve concrete type for abstract type %s`, def.Name())
+	}
+
+	typ, ok := ctx.lookupType(name)
+	if !ok {
+		return nil, errors.Errorf(`type %s (resolved from %s) not found`, name, def.Name())
+	}
+	obj, ok := typ.(model.ObjectDefinition)
+	if !ok {
+		return nil, errors.Errorf(`type %s (resolved from %s) is not an object type`, name, def.Name())
+	}
+	return obj, nil
+}
+
+// fieldSet holds the fields collected from a selection set,
+// grouped by their response keys
+type fieldSet struct {
+	keys   []string
+	fields map[string][]model.SelectionField
+}
+
+func (s *fieldSet) add(key string, f model.SelectionField) {
+	if s.fields == nil {
+		s.fields = make(map[string][]model.SelectionField)
+	}
+	if _, ok := s.fields[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.fields[key] = append(s.fields[key], f)
+}
+
+func (ctx *execCtx) collectFields(objType model.ObjectDefinition, selections chan model.Selection, visited map[string]struct{}) (*fieldSet, error) {
+	var set fieldSet
+	if err := ctx.collectFieldsInto(&set, objType, selections, visited); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+func (ctx *execCtx) collectFieldsInto(set *fieldSet, objType model.ObjectDefinition, selections chan model.Selection, visited map[string]struct{}) error {
+	for sel := range selections {
+		dc, ok := sel.(model.DirectivesContainer)
+		if ok {
+			include, err := ctx.shouldInclude(dc.Directives())
+			if err != nil {
+				return errors.Wrap(err, `failed to evaluate directives`)
+			}
+			if !include {
+				continue
+			}
+		}
+
+		switch sel.(type) {
+		case model.SelectionField:
+			f := sel.(model.SelectionField)
+			key := f.Name()
+			if f.HasAlias() {
+				key = f.Alias()
+			}
+			set.add(key, f)
+		case model.FragmentSpread:
+			name := sel.(model.FragmentSpread).Name()
+			if _, ok := visited[name]; ok {
+				continue
+			}
+			visited[name] = struct{}{}
+
+			frag, ok := ctx.doc.LookupFragment(name)
+			if !ok {
+				return errors.Errorf(`unknown fragment "%s"`, name)
+			}
+			if !ctx.doesFragmentTypeApply(objType, frag.Type()) {
+				continue
+			}
+			if err := ctx.collectFieldsInto(set, objType, frag.Selections(), visited); err != nil {
+				return err
+			}
+		case model.InlineFragment:
+			frag := sel.(model.InlineFragment)
+			if cond := frag.TypeCondition(); cond != nil && !ctx.doesFragmentTypeApply(objType, cond) {
+				continue
+			}
+			if err := ctx.collectFieldsInto(set, objType, frag.Selections(), visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ctx *execCtx) doesFragmentTypeApply(objType model.ObjectDefinition, cond model.Type) bool {
+	name := model.TypeName(cond)
+	if name == objType.Name() {
+		return true
+	}
+
+	def, ok := ctx.lookupType(name)
+	if !ok {
+		return false
+	}
+
+	switch def.(type) {
+	case model.InterfaceDefinition:
+		return objType.Implements(name)
+	case model.UnionDefinition:
+		for t := range def.(model.UnionDefinition).Types() {
+			if model.TypeName(t) == objType.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shouldInclude evaluates the @skip and @include directives
+func (ctx *execCtx) shouldInclude(directives chan model.Directive) (bool, error) {
+	include := true
+	for d := range directives {
+		switch d.Name() {
+		case "skip", "include":
+		default:
+			continue
+		}
+
+		var cond interface{}
+		for arg := range d.Arguments() {
+			if arg.Name() != "if" {
+				continue
+			}
+			v, err := ctx.literalValue(arg.Value())
+			if err != nil {
+				return false, errors.Wrapf(err, `failed to evaluate @%s`, d.Name())
+			}
+			cond = v
+		}
+
+		b, ok := cond.(bool)
+		if !ok {
+			return false, errors.Errorf(`argument "if" of @%s must be a boolean`, d.Name())
+		}
+
+		if (d.Name() == "skip" && b) || (d.Name() == "include" && !b) {
+			include = false
+		}
+	}
+	return include, nil
+}
+
+// lookupType looks up the type `name` in the schema. The built-in
+// introspection types are also looked up
+func (ctx *execCtx) lookupType(name string) (model.Definition, bool) {
+	if def, ok := introspection.LookupType(name); ok {
+		return def, true
+	}
+	if def, ok := ctx.schema.LookupType(name); ok {
+		return def, true
+	}
+	if def, ok := model.LookupBuiltinScalar(name); ok {
+		return def, true
+	}
+	return nil, false
+}
+
+// lookupField looks up the field `name` in `obj`. The __schema and
+// __type meta fields are available on the query root type
+func (ctx *execCtx) lookupField(obj model.ObjectDefinition, name string) (model.ObjectFieldDefinition, bool) {
+	if obj == ctx.rootType && ctx.operation.OperationType() == model.OperationTypeQuery {
+		switch name {
+		case "__schema":
+			return schemaMetaField, true
+		case "__type":
+			return typeMetaField, true
+		}
+	}
+
+	for f := range obj.Fields() {
+		if f.Name() == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	l := make([]interface{}, len(path)+1)
+	copy(l, path)
+	l[len(path)] = elem
+	return l
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,952 @@
+package execute_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lestrrat/go-graphql/execute"
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/parser"
+	"github.com/lestrrat/go-graphql/schema"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// executeSuccess returns a test that executes the operation in `src`
+// against the schema `s`, with `root` as the root value, and checks
+// that the result marshals to `expected`
+func executeSuccess(s model.Document, root interface{}, src string, variables map[string]interface{}, expected string) (string, func(*testing.T)) {
+	return src, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		p := parser.New()
+		doc, err := p.ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return
+		}
+
+		res, err := execute.Execute(ctx, s, doc, "", variables, root)
+		if !assert.NoError(t, err, "execute.Execute should succeed") {
+			return
+		}
+
+		buf, err := json.Marshal(res)
+		if !assert.NoError(t, err, "json.Marshal should succeed") {
+			return
+		}
+
+		if !assert.JSONEq(t, expected, string(buf), "result should match") {
+			t.Logf("%s", buf)
+			return
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Run(executeSuccess(schema.StarWars, nil, `query HeroNameQuery {
+  hero {
+    name
+  }
+}`, nil, `{"data":{"hero":{"name":"R2-D2"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query HeroNameAndFriendsQuery {
+  hero {
+    id
+    name
+    friends {
+      name
+    }
+  }
+}`, nil, `{"data":{"hero":{"id":"2001","name":"R2-D2","friends":[{"name":"Luke Skywalker"},{"name":"Han Solo"},{"name":"Leia Organa"}]}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query FetchLukeQuery {
+  human(id: "1000") {
+    name
+  }
+}`, nil, `{"data":{"human":{"name":"Luke Skywalker"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query FetchSomeIDQuery($someId: String!) {
+  human(id: $someId) {
+    name
+  }
+}`, map[string]interface{}{"someId": "1002"}, `{"data":{"human":{"name":"Han Solo"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query FetchLukeAndLeiaAliased {
+  luke: human(id: "1000") {
+    name
+  }
+  leia: human(id: "1003") {
+    name
+  }
+}`, nil, `{"data":{"luke":{"name":"Luke Skywalker"},"leia":{"name":"Leia Organa"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query HeroForEpisode {
+  hero(episode: EMPIRE) {
+    name
+    appearsIn
+  }
+}`, nil, `{"data":{"hero":{"name":"Luke Skywalker","appearsIn":["NEWHOPE","EMPIRE","JEDI"]}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query UseFragment {
+  luke: human(id: "1000") {
+    ...HumanFragment
+  }
+}
+
+fragment HumanFragment on Human {
+  name
+  homePlanet
+}`, nil, `{"data":{"luke":{"name":"Luke Skywalker","homePlanet":"Tatooine"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query CheckTypeOfR2 {
+  hero {
+    name
+    ... on Droid {
+      primaryFunction
+    }
+    ... on Human {
+      homePlanet
+    }
+  }
+}`, nil, `{"data":{"hero":{"name":"R2-D2","primaryFunction":"Astromech"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query HeroSkip($skip: Boolean!) {
+  hero {
+    name
+    id @skip(if: $skip)
+  }
+}`, map[string]interface{}{"skip": true}, `{"data":{"hero":{"name":"R2-D2"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query HeroNameQuery {
+  hero {
+    name
+    secretBackstory
+  }
+}`, nil, `{"data":{"hero":{"name":"R2-D2","secretBackstory":null}},"errors":[{"message":"secretBackstory is secret.","path":["hero","secretBackstory"]}]}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query CheckTypeOfLuke {
+  hero(episode: EMPIRE) {
+    __typename
+    name
+  }
+}`, nil, `{"data":{"hero":{"__typename":"Human","name":"Luke Skywalker"}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query HeroForEpisodeVariable($ep: Episode) {
+  hero(episode: $ep) {
+    name
+  }
+}`, map[string]interface{}{"ep": "EMPIRE"}, `{"data":{"hero":{"name":"Luke Skywalker"}}}`))
+}
+
+func TestIntrospection(t *testing.T) {
+	t.Run(executeSuccess(schema.StarWars, nil, `query IntrospectionQueryTypeQuery {
+  __schema {
+    queryType {
+      name
+    }
+  }
+}`, nil, `{"data":{"__schema":{"queryType":{"name":"Query"}}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query IntrospectionDroidTypeQuery {
+  __type(name: "Droid") {
+    name
+    kind
+    interfaces {
+      name
+    }
+    fields {
+      name
+      type {
+        name
+        kind
+        ofType {
+          name
+          kind
+        }
+      }
+    }
+  }
+}`, nil, `{"data":{"__type":{"name":"Droid","kind":"OBJECT","interfaces":[{"name":"Character"}],"fields":[
+  {"name":"id","type":{"name":null,"kind":"NON_NULL","ofType":{"name":"String","kind":"SCALAR"}}},
+  {"name":"name","type":{"name":"String","kind":"SCALAR","ofType":null}},
+  {"name":"friends","type":{"name":null,"kind":"LIST","ofType":{"name":"Character","kind":"INTERFACE"}}},
+  {"name":"appearsIn","type":{"name":null,"kind":"LIST","ofType":{"name":"Episode","kind":"ENUM"}}},
+  {"name":"secretBackstory","type":{"name":"String","kind":"SCALAR","ofType":null}},
+  {"name":"primaryFunction","type":{"name":"String","kind":"SCALAR","ofType":null}}
+]}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query IntrospectionCharacterKindQuery {
+  __type(name: "Character") {
+    name
+    kind
+    possibleTypes {
+      name
+    }
+  }
+}`, nil, `{"data":{"__type":{"name":"Character","kind":"INTERFACE","possibleTypes":[{"name":"Human"},{"name":"Droid"}]}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query IntrospectionDirectivesQuery {
+  __schema {
+    directives {
+      name
+      locations
+      isRepeatable
+      args {
+        name
+        defaultValue
+      }
+    }
+  }
+}`, nil, `{"data":{"__schema":{"directives":[
+  {"name":"include","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"isRepeatable":false,"args":[{"name":"if","defaultValue":null}]},
+  {"name":"skip","locations":["FIELD","FRAGMENT_SPREAD","INLINE_FRAGMENT"],"isRepeatable":false,"args":[{"name":"if","defaultValue":null}]},
+  {"name":"deprecated","locations":["FIELD_DEFINITION","ARGUMENT_DEFINITION","INPUT_FIELD_DEFINITION","ENUM_VALUE"],"isRepeatable":false,"args":[{"name":"reason","defaultValue":"\"No longer supported\""}]},
+  {"name":"specifiedBy","locations":["SCALAR"],"isRepeatable":false,"args":[{"name":"url","defaultValue":null}]}
+]}}}`))
+	t.Run(executeSuccess(schema.StarWars, nil, `query IntrospectionDescriptionQuery {
+  __type(name: "Episode") {
+    name
+    description
+    enumValues {
+      name
+      description
+    }
+  }
+}`, nil, `{"data":{"__type":{"name":"Episode","description":"One of the films in the Star Wars Trilogy","enumValues":[
+  {"name":"NEWHOPE","description":"Released in 1977."},
+  {"name":"EMPIRE","description":"Released in 1980."},
+  {"name":"JEDI","description":"Released in 1983."}
+]}}}`))
+	t.Run("IntrospectionQuery", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		buf, err := execute.Introspect(ctx, schema.StarWars)
+		if !assert.NoError(t, err, "execute.Introspect should succeed") {
+			return
+		}
+
+		var res struct {
+			Data struct {
+				Schema struct {
+					Types []struct {
+						Name string `json:"name"`
+					} `json:"types"`
+				} `json:"__schema"`
+			} `json:"data"`
+			Errors []interface{} `json:"errors"`
+		}
+		if !assert.NoError(t, json.Unmarshal(buf, &res), "json.Unmarshal should succeed") {
+			return
+		}
+		if !assert.Empty(t, res.Errors, "there should be no errors") {
+			t.Logf("%s", buf)
+			return
+		}
+
+		var names []string
+		for _, typ := range res.Data.Schema.Types {
+			names = append(names, typ.Name)
+		}
+		for _, name := range []string{"Query", "Human", "Droid", "Character", "Episode", "String", "__Schema", "__Type"} {
+			if !assert.Contains(t, names, name, "types should contain %s", name) {
+				return
+			}
+		}
+	})
+}
+
+func TestIntrospectDeprecated(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s, err := parser.New().ParseString(ctx, `enum Color {
+  RED
+  GREEN @deprecated
+  BLUE @deprecated(reason: "Use RED")
+}
+
+interface Named {
+  name: String
+  title: String @deprecated(reason: "Use name")
+}
+
+type Query {
+  color: Color
+  colour: Color @deprecated
+  named: Named
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{
+			query: `{
+  __type(name: "Query") {
+    fields { name isDeprecated deprecationReason }
+  }
+}`,
+			expected: `{"data":{"__type":{"fields":[
+  {"name":"color","isDeprecated":false,"deprecationReason":null},
+  {"name":"named","isDeprecated":false,"deprecationReason":null}
+]}}}`,
+		},
+		{
+			query: `{
+  __type(name: "Query") {
+    fields(includeDeprecated: true) { name isDeprecated deprecationReason }
+  }
+}`,
+			expected: `{"data":{"__type":{"fields":[
+  {"name":"color","isDeprecated":false,"deprecationReason":null},
+  {"name":"colour","isDeprecated":true,"deprecationReason":"No longer supported"},
+  {"name":"named","isDeprecated":false,"deprecationReason":null}
+]}}}`,
+		},
+		{
+			query: `{
+  __type(name: "Named") {
+    current: fields { name }
+    all: fields(includeDeprecated: true) { name isDeprecated deprecationReason }
+  }
+}`,
+			expected: `{"data":{"__type":{"current":[{"name":"name"}],"all":[
+  {"name":"name","isDeprecated":false,"deprecationReason":null},
+  {"name":"title","isDeprecated":true,"deprecationReason":"Use name"}
+]}}}`,
+		},
+		{
+			query: `{
+  __type(name: "Color") {
+    current: enumValues { name }
+    all: enumValues(includeDeprecated: true) { name isDeprecated deprecationReason }
+  }
+}`,
+			expected: `{"data":{"__type":{"current":[{"name":"RED"}],"all":[
+  {"name":"RED","isDeprecated":false,"deprecationReason":null},
+  {"name":"GREEN","isDeprecated":true,"deprecationReason":"No longer supported"},
+  {"name":"BLUE","isDeprecated":true,"deprecationReason":"Use RED"}
+]}}}`,
+		},
+	} {
+		t.Run(executeSuccess(s, nil, tc.query, nil, tc.expected))
+	}
+}
+
+func TestIntrospectInterfaceFieldArguments(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s, err := parser.New().ParseString(ctx, `interface Node {
+  id: ID!
+  children(first: Int = 10, after: ID): [Node]
+}
+
+type Query {
+  node: Node
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	t.Run(executeSuccess(s, nil, `{
+  __type(name: "Node") {
+    fields { name args { name defaultValue type { name } } }
+  }
+}`, nil, `{"data":{"__type":{"fields":[
+  {"name":"id","args":[]},
+  {"name":"children","args":[
+    {"name":"first","defaultValue":"10","type":{"name":"Int"}},
+    {"name":"after","defaultValue":null,"type":{"name":"ID"}}
+  ]}
+]}}}`))
+}
+
+func TestExecuteErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	doc, err := p.ParseString(ctx, `query A { hero { name } } query B { hero { id } }`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	t.Run("Missing operation name", func(t *testing.T) {
+		_, err := execute.Execute(ctx, schema.StarWars, doc, "", nil, nil)
+		if !assert.Error(t, err, "execute.Execute should fail") {
+			return
+		}
+	})
+	t.Run("Unknown operation name", func(t *testing.T) {
+		_, err := execute.Execute(ctx, schema.StarWars, doc, "C", nil, nil)
+		if !assert.Error(t, err, "execute.Execute should fail") {
+			return
+		}
+	})
+	t.Run("Invalid enum variable", func(t *testing.T) {
+		doc, err := p.ParseString(ctx, `query Q($ep: Episode) { hero(episode: $ep) { name } }`)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return
+		}
+		for _, ep := range []interface{}{"BOGUS", 5} {
+			_, err = execute.Execute(ctx, schema.StarWars, doc, "Q", map[string]interface{}{"ep": ep}, nil)
+			if !assert.Error(t, err, "execute.Execute should fail") {
+				return
+			}
+		}
+	})
+	t.Run("Missing required variable", func(t *testing.T) {
+		doc, err := p.ParseString(ctx, `query Q($id: String!) { human(id: $id) { name } }`)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return
+		}
+		_, err = execute.Execute(ctx, schema.StarWars, doc, "Q", nil, nil)
+		if !assert.Error(t, err, "execute.Execute should fail") {
+			return
+		}
+	})
+}
+
+type testPlanet struct {
+	Name       string
+	Population int `graphql:"inhabitants"`
+}
+
+type testEntity struct {
+	ID string
+}
+
+type testPerson struct {
+	testEntity
+	Name   string
+	Planet *testPlanet
+	Tags   []string
+	Secret string `graphql:"-"`
+}
+
+func (p *testPerson) Greeting(args map[string]interface{}) string {
+	return args["greeting"].(string) + ", " + p.Name
+}
+
+func (p testPerson) NameLength(ctx context.Context) (int, error) {
+	return len(p.Name), nil
+}
+
+func TestDefaultResolveField(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `type Query {
+  me: Person
+  people: [Person]
+  config: Config
+}
+
+type Person {
+  id: ID
+  name: String
+  planet: Planet
+  tags: [String]
+  greeting(greeting: String): String
+  nameLength: Int
+  secret: String
+}
+
+type Planet {
+  name: String
+  inhabitants: Int
+}
+
+type Config {
+  version: String
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	root := struct {
+		Me     *testPerson
+		People []testPerson
+		Config map[string]interface{}
+	}{
+		Me: &testPerson{
+			testEntity: testEntity{ID: "1"},
+			Name:       "Luke",
+			Planet:     &testPlanet{Name: "Tatooine", Population: 200000},
+			Tags:       []string{"jedi", "pilot"},
+			Secret:     "father",
+		},
+		People: []testPerson{{Name: "Han"}, {Name: "Leia"}},
+		Config: map[string]interface{}{"version": "1.0"},
+	}
+
+	t.Run(executeSuccess(s, root, `{
+  me {
+    id
+    name
+    planet {
+      name
+      inhabitants
+    }
+    tags
+    greeting(greeting: "Hello")
+    nameLength
+    secret
+  }
+  people {
+    name
+    greeting(greeting: "Hi")
+  }
+  config {
+    version
+  }
+}`, nil, `{"data":{"me":{"id":"1","name":"Luke","planet":{"name":"Tatooine","inhabitants":200000},"tags":["jedi","pilot"],"greeting":"Hello, Luke","nameLength":4,"secret":null},"people":[{"name":"Han","greeting":"Hi, Han"},{"name":"Leia","greeting":"Hi, Leia"}],"config":{"version":"1.0"}}}`))
+}
+
+type testCalculator struct{}
+
+func (testCalculator) Sum(args map[string]interface{}) int {
+	var sum int
+	for _, v := range args["values"].([]interface{}) {
+		sum += v.(int)
+	}
+	return sum
+}
+
+func (testCalculator) Count(args map[string]interface{}) int {
+	var count int
+	for _, row := range args["matrix"].([]interface{}) {
+		count += len(row.([]interface{}))
+	}
+	return count
+}
+
+func TestListArguments(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `type Query {
+  sum(values: [Int!]!): Int
+  count(matrix: [[Int]] = [[1, 2], [3]]): Int
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	for _, tc := range []struct {
+		query     string
+		variables map[string]interface{}
+		expected  string
+	}{
+		{
+			query:     `query Q($three: Int!) { sum(values: [1, 2, $three]) }`,
+			variables: map[string]interface{}{"three": 3},
+			expected:  `{"data":{"sum":6}}`,
+		},
+		{
+			query:    `{ single: sum(values: 5) }`,
+			expected: `{"data":{"single":5}}`,
+		},
+		{
+			query:     `query Q($values: [Int!]!) { sum(values: $values) }`,
+			variables: map[string]interface{}{"values": []interface{}{4, 5}},
+			expected:  `{"data":{"sum":9}}`,
+		},
+		{
+			query:    `{ count }`,
+			expected: `{"data":{"count":3}}`,
+		},
+		{
+			query:    `{ empty: count(matrix: [[], []]) }`,
+			expected: `{"data":{"empty":0}}`,
+		},
+	} {
+		t.Run(executeSuccess(s, testCalculator{}, tc.query, tc.variables, tc.expected))
+	}
+}
+
+type testCatalog struct{}
+
+func (testCatalog) Search(args map[string]interface{}) string {
+	filter := args["filter"].(map[string]interface{})
+	return fmt.Sprintf("%v/%v", filter["query"], filter["limit"])
+}
+
+func (testCatalog) Order(args map[string]interface{}) string {
+	filter := args["filter"].(map[string]interface{})
+	return fmt.Sprintf("%v", filter["order"])
+}
+
+func TestInputFieldDefaults(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `enum Order {
+  ASC
+  DESC
+}
+
+input Filter {
+  query: String!
+  limit: Int = 10
+  order: Order = DESC
+}
+
+type Query {
+  search(filter: Filter = {query: "all"}): String
+  order(filter: Filter!): String
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	for _, tc := range []struct {
+		query     string
+		variables map[string]interface{}
+		expected  string
+	}{
+		{
+			query:    `{ search }`,
+			expected: `{"data":{"search":"all/10"}}`,
+		},
+		{
+			query:    `{ literal: search(filter: {query: "go"}) }`,
+			expected: `{"data":{"literal":"go/10"}}`,
+		},
+		{
+			query:    `{ explicit: search(filter: {query: "go", limit: 5}) }`,
+			expected: `{"data":{"explicit":"go/5"}}`,
+		},
+		{
+			query:    `query Q($limit: Int) { unset: search(filter: {query: "go", limit: $limit}) }`,
+			expected: `{"data":{"unset":"go/10"}}`,
+		},
+		{
+			query:     `query Q($filter: Filter!) { variable: search(filter: $filter) }`,
+			variables: map[string]interface{}{"filter": map[string]interface{}{"query": "graphql"}},
+			expected:  `{"data":{"variable":"graphql/10"}}`,
+		},
+		{
+			query:    `{ order(filter: {query: "go"}) }`,
+			expected: `{"data":{"order":"2"}}`,
+		},
+		{
+			query:    `{ order(filter: {query: "go", order: ASC}) }`,
+			expected: `{"data":{"order":"1"}}`,
+		},
+		{
+			query:     `query Q($filter: Filter!) { order(filter: $filter) }`,
+			variables: map[string]interface{}{"filter": map[string]interface{}{"query": "go", "order": "ASC"}},
+			expected:  `{"data":{"order":"1"}}`,
+		},
+		{
+			query:     `query Q($filter: Filter!) { order(filter: $filter) }`,
+			variables: map[string]interface{}{"filter": map[string]interface{}{"query": "go"}},
+			expected:  `{"data":{"order":"2"}}`,
+		},
+		{
+			query:    `{ search(filter: {query: null}) }`,
+			expected: `{"data":{"search":null},"errors":[{"message":"failed to coerce argument \"filter\": field \"query\" of required type String! must not be null","path":["search"]}]}`,
+		},
+		{
+			query:     `query Q($query: String) { search(filter: {query: $query}) }`,
+			variables: map[string]interface{}{"query": nil},
+			expected:  `{"data":{"search":null},"errors":[{"message":"failed to coerce argument \"filter\": field \"query\" of required type String! must not be null","path":["search"]}]}`,
+		},
+	} {
+		t.Run(executeSuccess(s, testCatalog{}, tc.query, tc.variables, tc.expected))
+	}
+
+	doc, err := p.ParseString(ctx, `query Q($filter: Filter!) { search(filter: $filter) }`)
+	if !assert.NoError(t, err, "p.Parse should succeed (query)") {
+		return
+	}
+
+	t.Run("Missing required field in variable", func(t *testing.T) {
+		_, err := execute.Execute(ctx, s, doc, "", map[string]interface{}{"filter": map[string]interface{}{"limit": 1}}, testCatalog{})
+		if !assert.Error(t, err, "execute.Execute should fail") {
+			return
+		}
+	})
+
+	t.Run("Unknown field in variable", func(t *testing.T) {
+		_, err := execute.Execute(ctx, s, doc, "", map[string]interface{}{"filter": map[string]interface{}{"query": "go", "offset": 1}}, testCatalog{})
+		if !assert.Error(t, err, "execute.Execute should fail") {
+			return
+		}
+	})
+
+	t.Run("Invalid enum field in variable", func(t *testing.T) {
+		_, err := execute.Execute(ctx, s, doc, "", map[string]interface{}{"filter": map[string]interface{}{"query": "go", "order": "UP"}}, testCatalog{})
+		if !assert.Error(t, err, "execute.Execute should fail") {
+			return
+		}
+	})
+}
+
+func TestExecuteSubscription(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `schema {
+  query: Query
+  subscription: Subscription
+}
+
+type Query {
+  ping: String
+}
+
+type Subscription {
+  reviewAdded: Review
+}
+
+type Review {
+  stars: Int
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	doc, err := p.ParseString(ctx, `subscription OnReview {
+  reviewAdded {
+    stars
+  }
+}
+
+query SubscriptionType {
+  __schema {
+    subscriptionType {
+      name
+    }
+  }
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (query)") {
+		return
+	}
+
+	if _, ok := doc.LookupSubscription("OnReview"); !assert.True(t, ok, "doc.LookupSubscription should succeed") {
+		return
+	}
+
+	root := map[string]interface{}{
+		"reviewAdded": map[string]interface{}{"stars": 5},
+	}
+	for name, expected := range map[string]string{
+		"OnReview":         `{"data":{"reviewAdded":{"stars":5}}}`,
+		"SubscriptionType": `{"data":{"__schema":{"subscriptionType":{"name":"Subscription"}}}}`,
+	} {
+		res, err := execute.Execute(ctx, s, doc, name, nil, root)
+		if !assert.NoError(t, err, "execute.Execute should succeed") {
+			return
+		}
+
+		buf, err := json.Marshal(res)
+		if !assert.NoError(t, err, "json.Marshal should succeed") {
+			return
+		}
+
+		if !assert.JSONEq(t, expected, string(buf), "result should match") {
+			t.Logf("%s", buf)
+			return
+		}
+	}
+}
+
+type testClock struct{}
+
+func (testClock) Tomorrow(args map[string]interface{}) time.Time {
+	return args["date"].(time.Time).AddDate(0, 0, 1)
+}
+
+func TestCustomScalar(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+type Query {
+  tomorrow(date: Date!): Date
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	def, ok := s.LookupType("Date")
+	if !assert.True(t, ok, "s.LookupType should succeed") {
+		return
+	}
+
+	const layout = "2006-01-02"
+	parseDate := func(v interface{}) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf(`expected string, got %T`, v)
+		}
+		return time.Parse(layout, s)
+	}
+	scalar := def.(model.ScalarDefinition)
+	scalar.SetSerializeFunc(func(v interface{}) (interface{}, error) {
+		return v.(time.Time).Format(layout), nil
+	})
+	scalar.SetParseValueFunc(parseDate)
+	scalar.SetParseLiteralFunc(func(v model.Value) (interface{}, error) {
+		return parseDate(v.Value())
+	})
+
+	for _, tc := range []struct {
+		query     string
+		variables map[string]interface{}
+		expected  string
+	}{
+		{
+			query:    `{ tomorrow(date: "2017-01-31") }`,
+			expected: `{"data":{"tomorrow":"2017-02-01"}}`,
+		},
+		{
+			query:     `query Q($date: Date!) { later: tomorrow(date: $date) }`,
+			variables: map[string]interface{}{"date": "2017-02-28"},
+			expected:  `{"data":{"later":"2017-03-01"}}`,
+		},
+	} {
+		t.Run(executeSuccess(s, testClock{}, tc.query, tc.variables, tc.expected))
+	}
+
+	t.Run("Invalid variable value", func(t *testing.T) {
+		doc, err := p.ParseString(ctx, `query Q($date: Date!) { tomorrow(date: $date) }`)
+		if !assert.NoError(t, err, "p.Parse should succeed (query)") {
+			return
+		}
+		_, err = execute.Execute(ctx, s, doc, "", map[string]interface{}{"date": 20170228}, testClock{})
+		if !assert.Error(t, err, "execute.Execute should fail") {
+			return
+		}
+	})
+}
+
+type testNumbers struct {
+	Big      int64
+	Fraction float64
+	Whole    float64
+}
+
+func (testNumbers) Echo(args map[string]interface{}) interface{} {
+	return args["n"]
+}
+
+func TestIntRange(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `type Query {
+  big: Int
+  fraction: Int
+  whole: Int
+  echo(n: Int): Int
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	root := testNumbers{Big: 1 << 40, Fraction: 1.5, Whole: 3}
+	t.Run(executeSuccess(s, root, `{ whole big fraction }`, nil, `{"data":{"whole":3,"big":null,"fraction":null},"errors":[
+  {"message":"Int can not represent 1099511627776: out of range","path":["big"]},
+  {"message":"Int can not represent non-integral value 1.5","path":["fraction"]}
+]}`))
+	t.Run(executeSuccess(s, root, `query Q($n: Int) { echo(n: $n) }`, map[string]interface{}{"n": float64(2147483647)}, `{"data":{"echo":2147483647}}`))
+
+	for _, tc := range []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+	}{
+		{
+			name:      "Variable out of range",
+			query:     `query Q($n: Int) { echo(n: $n) }`,
+			variables: map[string]interface{}{"n": 1e20},
+		},
+		{
+			name:      "Non-integral variable",
+			query:     `query Q($n: Int) { echo(n: $n) }`,
+			variables: map[string]interface{}{"n": 2.5},
+		},
+		{
+			name:      "Variable just out of range",
+			query:     `query Q($n: Int) { echo(n: $n) }`,
+			variables: map[string]interface{}{"n": 2147483648},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := p.ParseString(ctx, tc.query)
+			if !assert.NoError(t, err, "p.Parse should succeed (query)") {
+				return
+			}
+			_, err = execute.Execute(ctx, s, doc, "", tc.variables, root)
+			if !assert.Error(t, err, "execute.Execute should fail") {
+				return
+			}
+		})
+	}
+
+	t.Run(executeSuccess(s, root, `{ echo(n: 2147483648) }`, nil, `{"data":{"echo":null},"errors":[
+  {"message":"failed to coerce argument \"n\": invalid value for type Int: Int can not represent 2147483648: out of range","path":["echo"]}
+]}`))
+}
+
+func TestBuiltinScalarsAreImmutable(t *testing.T) {
+	def, ok := model.LookupBuiltinScalar("Int")
+	if !assert.True(t, ok, "model.LookupBuiltinScalar should succeed") {
+		return
+	}
+	def.SetSerializeFunc(func(interface{}) (interface{}, error) {
+		return "changed", nil
+	})
+
+	for _, def := range model.BuiltinScalars() {
+		def.SetSerializeFunc(func(interface{}) (interface{}, error) {
+			return "changed", nil
+		})
+	}
+
+	def, _ = model.LookupBuiltinScalar("Int")
+	v, err := def.Serialize(1)
+	if !assert.NoError(t, err, "def.Serialize should succeed") {
+		return
+	}
+	if !assert.Equal(t, 1, v, "built-in Int should not be modified") {
+		return
+	}
+
+	// neither is the String scalar used to serialize names
+	t.Run(executeSuccess(schema.StarWars, nil, `{ hero { name } }`, nil, `{"data":{"hero":{"name":"R2-D2"}}}`))
+}
+
+func TestBuiltinDirectivesAreImmutable(t *testing.T) {
+	def, ok := model.LookupBuiltinDirective("deprecated")
+	if !assert.True(t, ok, "model.LookupBuiltinDirective should succeed") {
+		return
+	}
+	def.SetDescription("changed")
+	def.AddArguments(model.NewObjectFieldArgumentDefinition("since", model.NewNamedType("String")))
+	(<-def.Arguments()).SetDefaultValue(model.NewStringValue("changed"))
+
+	for _, def := range model.BuiltinDirectives() {
+		def.SetDescription("changed")
+	}
+
+	def, _ = model.LookupBuiltinDirective("deprecated")
+	if !assert.Equal(t, "Marks an element of a GraphQL schema as no longer supported.", def.Description(), "built-in @deprecated should not be modified") {
+		return
+	}
+	args := def.Arguments()
+	if !assert.Len(t, args, 1, "built-in @deprecated should not gain arguments") {
+		return
+	}
+	if !assert.Equal(t, "No longer supported", (<-args).DefaultValue().Value(), "default reason should not be modified") {
+		return
+	}
+
+	t.Run(executeSuccess(schema.StarWars, nil, `{ hero { name id @skip(if: true) } }`, nil, `{"data":{"hero":{"name":"R2-D2"}}}`))
+}
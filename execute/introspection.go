@@ -0,0 +1,660 @@
+package execute
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/lestrrat/go-graphql/dsl"
+	"github.com/lestrrat/go-graphql/format"
+	"github.com/lestrrat/go-graphql/introspection"
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/parser"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// IntrospectionQuery is the canonical query used by tools such as
+// GraphiQL to fetch the full type system of a schema
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      ...FullType
+    }
+    directives {
+      name
+      description
+      locations
+      args {
+        ...InputValue
+      }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args {
+      ...InputValue
+    }
+    type {
+      ...TypeRef
+    }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields {
+    ...InputValue
+  }
+  interfaces {
+    ...TypeRef
+  }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes {
+    ...TypeRef
+  }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// Introspect runs IntrospectionQuery against `schema`, and returns
+// the JSON encoded result
+func Introspect(ctx context.Context, schema model.Document) ([]byte, error) {
+	p := parser.New()
+	doc, err := p.ParseString(ctx, IntrospectionQuery)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to parse introspection query`)
+	}
+
+	res, err := Execute(ctx, schema, doc, "IntrospectionQuery", nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to execute introspection query`)
+	}
+
+	buf, err := json.Marshal(res)
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to marshal introspection result`)
+	}
+	return buf, nil
+}
+
+// schemaMetaField and typeMetaField are the definitions of the
+// __schema and __type fields, with the resolvers that provide the
+// values for the introspection types
+var schemaMetaField model.ObjectFieldDefinition
+var typeMetaField model.ObjectFieldDefinition
+
+func init() {
+	schemaMetaField = dsl.ObjectField(introspection.SchemaField.Name(), introspection.SchemaField.Type(),
+		dsl.FieldResolver(func(c context.Context, _ interface{}, _ map[string]interface{}) (interface{}, error) {
+			return c.(*execCtx).introspector(), nil
+		}),
+	)
+	typeMetaField = dsl.ObjectField(introspection.TypeField.Name(), introspection.TypeField.Type(),
+		dsl.FieldResolver(func(c context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+			name, _ := args["name"].(string)
+			t, ok := c.(*execCtx).introspector().types[name]
+			if !ok {
+				return nil, nil
+			}
+			return t, nil
+		}),
+	)
+	for arg := range introspection.TypeField.Arguments() {
+		typeMetaField.AddArguments(arg)
+	}
+}
+
+// introspector provides the values for the __Schema type. All of the
+// introspection values are exposed through methods, which are picked
+// up by DefaultResolveField
+type introspector struct {
+	schema model.Document
+	names  []string
+	types  map[string]*introspectedType
+}
+
+func (ctx *execCtx) introspector() *introspector {
+	if ctx.introspection == nil {
+		ctx.introspection = newIntrospector(ctx.schema)
+	}
+	return ctx.introspection
+}
+
+func newIntrospector(schema model.Document) *introspector {
+	s := &introspector{
+		schema: schema,
+		types:  make(map[string]*introspectedType),
+	}
+
+	var referenced []model.Type
+	for def := range schema.Definitions() {
+		var kind string
+		switch def.(type) {
+		case model.ObjectDefinition:
+			kind = introspection.KindObject
+			for f := range def.(model.ObjectDefinition).Fields() {
+				referenced = append(referenced, f.Type())
+				for arg := range f.Arguments() {
+					referenced = append(referenced, arg.Type())
+				}
+			}
+		case model.InterfaceDefinition:
+			kind = introspection.KindInterface
+			for f := range def.(model.InterfaceDefinition).Fields() {
+				referenced = append(referenced, f.Type())
+			}
+		case model.UnionDefinition:
+			kind = introspection.KindUnion
+		case model.EnumDefinition:
+			kind = introspection.KindEnum
+		case model.InputDefinition:
+			kind = introspection.KindInputObject
+			for f := range def.(model.InputDefinition).Fields() {
+				referenced = append(referenced, f.Type())
+			}
+		case model.ScalarDefinition:
+			kind = introspection.KindScalar
+		default:
+			continue
+		}
+		s.addType(&introspectedType{schema: s, kind: kind, name: def.Name(), def: def})
+	}
+
+	// The built-in scalars are always registered, along with any
+	// name that is referenced but not defined
+	scalars := make(map[string]model.Definition)
+	for _, def := range model.BuiltinScalars() {
+		scalars[def.Name()] = def
+	}
+	for _, t := range referenced {
+		name := model.TypeName(t)
+		if _, ok := scalars[name]; ok {
+			continue
+		}
+		if _, ok := introspection.LookupType(name); ok {
+			continue
+		}
+		scalars[name] = nil
+	}
+
+	var names []string
+	for name := range scalars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := s.types[name]; ok {
+			continue
+		}
+		s.addType(&introspectedType{schema: s, kind: introspection.KindScalar, name: name, def: scalars[name]})
+	}
+
+	for _, def := range introspection.Types() {
+		kind := introspection.KindObject
+		if _, ok := def.(model.EnumDefinition); ok {
+			kind = introspection.KindEnum
+		}
+		s.addType(&introspectedType{schema: s, kind: kind, name: def.Name(), def: def})
+	}
+	return s
+}
+
+func (s *introspector) addType(t *introspectedType) {
+	s.names = append(s.names, t.name)
+	s.types[t.name] = t
+}
+
+// typeOf returns the introspected type for the type reference `t`,
+// wrapping it in LIST and NON_NULL types as necessary
+func (s *introspector) typeOf(t model.Type) *introspectedType {
+	var it *introspectedType
+	if lt, ok := t.(model.ListType); ok {
+		it = &introspectedType{schema: s, kind: introspection.KindList, ofType: s.typeOf(lt.Type())}
+	} else {
+		name := model.TypeName(t)
+		var ok bool
+		if it, ok = s.types[name]; !ok {
+			it = &introspectedType{schema: s, kind: introspection.KindScalar, name: name}
+		}
+	}
+
+	if !model.IsNullable(t) {
+		it = &introspectedType{schema: s, kind: introspection.KindNonNull, ofType: it}
+	}
+	return it
+}
+
+// arguments returns the introspected input values for the argument
+// definitions of a field or a directive
+func (s *introspector) arguments(ch chan model.ObjectFieldArgumentDefinition) []*introspectedInputValue {
+	list := []*introspectedInputValue{}
+	for arg := range ch {
+		list = append(list, &introspectedInputValue{
+			schema:       s,
+			name:         arg.Name(),
+			description:  arg.Description(),
+			typ:          arg.Type(),
+			defaultValue: arg,
+		})
+	}
+	return list
+}
+
+func (s *introspector) rootType(typ model.OperationType) *introspectedType {
+	var t model.NamedType
+	if def, ok := s.schema.LookupSchema(); ok {
+		switch typ {
+		case model.OperationTypeQuery:
+			t = def.Query()
+		case model.OperationTypeMutation:
+			t = def.Mutation()
+		case model.OperationTypeSubscription:
+			t = def.Subscription()
+		}
+		if t == nil {
+			return nil
+		}
+		return s.types[t.Name()]
+	}
+
+	switch typ {
+	case model.OperationTypeQuery:
+		return s.types["Query"]
+	case model.OperationTypeMutation:
+		return s.types["Mutation"]
+	case model.OperationTypeSubscription:
+		return s.types["Subscription"]
+	}
+	return nil
+}
+
+func (s *introspector) Types() []*introspectedType {
+	list := make([]*introspectedType, len(s.names))
+	for i, name := range s.names {
+		list[i] = s.types[name]
+	}
+	return list
+}
+
+func (s *introspector) QueryType() *introspectedType {
+	return s.rootType(model.OperationTypeQuery)
+}
+
+func (s *introspector) MutationType() *introspectedType {
+	return s.rootType(model.OperationTypeMutation)
+}
+
+func (s *introspector) SubscriptionType() *introspectedType {
+	return s.rootType(model.OperationTypeSubscription)
+}
+
+// Directives returns the built-in directives, followed by those that
+// are defined in the schema. A schema may override the definition of
+// a built-in directive
+func (s *introspector) Directives() []*introspectedDirective {
+	var list []*introspectedDirective
+	for _, def := range model.BuiltinDirectives() {
+		if sdef, ok := s.schema.LookupDirective(def.Name()); ok {
+			def = sdef
+		}
+		list = append(list, &introspectedDirective{schema: s, def: def})
+	}
+
+	for def := range s.schema.Definitions() {
+		ddef, ok := def.(model.DirectiveDefinition)
+		if !ok {
+			continue
+		}
+		if _, ok := model.LookupBuiltinDirective(ddef.Name()); ok {
+			continue
+		}
+		list = append(list, &introspectedDirective{schema: s, def: ddef})
+	}
+	return list
+}
+
+// description returns `s` as the value of a description field, which
+// is null when there is no description
+func description(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// introspectedType provides the values for the __Type type
+type introspectedType struct {
+	schema *introspector
+	kind   string
+	name   string
+	def    model.Definition
+	ofType *introspectedType
+}
+
+func (t *introspectedType) Kind() string {
+	return t.kind
+}
+
+func (t *introspectedType) Name() interface{} {
+	if t.name == "" {
+		return nil
+	}
+	return t.name
+}
+
+func (t *introspectedType) Description() interface{} {
+	if d, ok := t.def.(model.Describer); ok {
+		return description(d.Description())
+	}
+	return nil
+}
+
+// Fields returns the fields of object and interface types. Deprecated
+// fields are only included if the includeDeprecated argument is true
+func (t *introspectedType) Fields(args map[string]interface{}) []*introspectedField {
+	includeDeprecated, _ := args["includeDeprecated"].(bool)
+
+	var list []*introspectedField
+	add := func(field *introspectedField) {
+		if field.deprecated && !includeDeprecated {
+			return
+		}
+		list = append(list, field)
+	}
+
+	switch t.kind {
+	case introspection.KindObject:
+		for f := range t.def.(model.ObjectDefinition).Fields() {
+			field := &introspectedField{schema: t.schema, name: f.Name(), description: f.Description(), typ: f.Type()}
+			field.deprecated, field.deprecationReason = deprecation(f.Directives())
+			field.args = t.schema.arguments(f.Arguments())
+			add(field)
+		}
+	case introspection.KindInterface:
+		for f := range t.def.(model.InterfaceDefinition).Fields() {
+			field := &introspectedField{schema: t.schema, name: f.Name(), description: f.Description(), typ: f.Type()}
+			field.deprecated, field.deprecationReason = deprecation(f.Directives())
+			field.args = t.schema.arguments(f.Arguments())
+			add(field)
+		}
+	default:
+		return nil
+	}
+
+	if list == nil {
+		return []*introspectedField{}
+	}
+	return list
+}
+
+func (t *introspectedType) Interfaces() []*introspectedType {
+	impl, ok := t.def.(model.Implementer)
+	if !ok || (t.kind != introspection.KindObject && t.kind != introspection.KindInterface) {
+		return nil
+	}
+
+	list := []*introspectedType{}
+	for typ := range impl.Interfaces() {
+		list = append(list, t.schema.typeOf(typ))
+	}
+	return list
+}
+
+func (t *introspectedType) PossibleTypes() []*introspectedType {
+	var list []*introspectedType
+	switch t.kind {
+	case introspection.KindInterface:
+		list = []*introspectedType{}
+		for _, name := range t.schema.names {
+			pt := t.schema.types[name]
+			if pt.kind != introspection.KindObject {
+				continue
+			}
+			if pt.def.(model.ObjectDefinition).Implements(t.name) {
+				list = append(list, pt)
+			}
+		}
+	case introspection.KindUnion:
+		list = []*introspectedType{}
+		for typ := range t.def.(model.UnionDefinition).Types() {
+			list = append(list, t.schema.typeOf(typ))
+		}
+	}
+	return list
+}
+
+// EnumValues returns the values of enum types. Deprecated values are
+// only included if the includeDeprecated argument is true
+func (t *introspectedType) EnumValues(args map[string]interface{}) []*introspectedEnumValue {
+	if t.kind != introspection.KindEnum {
+		return nil
+	}
+	includeDeprecated, _ := args["includeDeprecated"].(bool)
+
+	list := []*introspectedEnumValue{}
+	for e := range t.def.(model.EnumDefinition).Elements() {
+		v := &introspectedEnumValue{name: e.Name(), description: e.Description()}
+		v.deprecated, v.deprecationReason = deprecation(e.Directives())
+		if v.deprecated && !includeDeprecated {
+			continue
+		}
+		list = append(list, v)
+	}
+	return list
+}
+
+func (t *introspectedType) InputFields() []*introspectedInputValue {
+	if t.kind != introspection.KindInputObject {
+		return nil
+	}
+
+	var list []*introspectedInputValue
+	for f := range t.def.(model.InputDefinition).Fields() {
+		list = append(list, &introspectedInputValue{schema: t.schema, name: f.Name(), description: f.Description(), typ: f.Type(), defaultValue: f})
+	}
+	return list
+}
+
+func (t *introspectedType) OfType() *introspectedType {
+	return t.ofType
+}
+
+// introspectedField provides the values for the __Field type
+type introspectedField struct {
+	schema      *introspector
+	name        string
+	description string
+	typ         model.Type
+	args        []*introspectedInputValue
+
+	deprecated        bool
+	deprecationReason string
+}
+
+func (f *introspectedField) Name() string {
+	return f.name
+}
+
+func (f *introspectedField) Description() interface{} {
+	return description(f.description)
+}
+
+func (f *introspectedField) Args() []*introspectedInputValue {
+	if f.args == nil {
+		return []*introspectedInputValue{}
+	}
+	return f.args
+}
+
+func (f *introspectedField) Type() *introspectedType {
+	return f.schema.typeOf(f.typ)
+}
+
+func (f *introspectedField) IsDeprecated() bool {
+	return f.deprecated
+}
+
+func (f *introspectedField) DeprecationReason() interface{} {
+	if !f.deprecated {
+		return nil
+	}
+	return f.deprecationReason
+}
+
+// introspectedInputValue provides the values for the __InputValue type
+type introspectedInputValue struct {
+	schema       *introspector
+	name         string
+	description  string
+	typ          model.Type
+	defaultValue model.DefaultValuer
+}
+
+func (v *introspectedInputValue) Name() string {
+	return v.name
+}
+
+func (v *introspectedInputValue) Description() interface{} {
+	return description(v.description)
+}
+
+func (v *introspectedInputValue) Type() *introspectedType {
+	return v.schema.typeOf(v.typ)
+}
+
+func (v *introspectedInputValue) DefaultValue() interface{} {
+	if v.defaultValue == nil || !v.defaultValue.HasDefaultValue() {
+		return nil
+	}
+	return format.Value(v.defaultValue.DefaultValue())
+}
+
+// introspectedEnumValue provides the values for the __EnumValue type
+type introspectedEnumValue struct {
+	name        string
+	description string
+
+	deprecated        bool
+	deprecationReason string
+}
+
+func (v *introspectedEnumValue) Name() string {
+	return v.name
+}
+
+func (v *introspectedEnumValue) Description() interface{} {
+	return description(v.description)
+}
+
+func (v *introspectedEnumValue) IsDeprecated() bool {
+	return v.deprecated
+}
+
+func (v *introspectedEnumValue) DeprecationReason() interface{} {
+	if !v.deprecated {
+		return nil
+	}
+	return v.deprecationReason
+}
+
+// defaultDeprecationReason is the default value of the reason argument
+// of @deprecated
+const defaultDeprecationReason = "No longer supported"
+
+// deprecation reports whether the @deprecated directive is found in
+// `ch`, and the reason that it gives
+func deprecation(ch chan model.Directive) (bool, string) {
+	for d := range ch {
+		if d.Name() != "deprecated" {
+			continue
+		}
+		for arg := range d.Arguments() {
+			if arg.Name() == "reason" && arg.Value().Kind() == model.StringKind {
+				return true, arg.Value().Value().(string)
+			}
+		}
+		return true, defaultDeprecationReason
+	}
+	return false, ""
+}
+
+// introspectedDirective provides the values for the __Directive type
+type introspectedDirective struct {
+	schema *introspector
+	def    model.DirectiveDefinition
+}
+
+func (d *introspectedDirective) Name() string {
+	return d.def.Name()
+}
+
+func (d *introspectedDirective) Description() interface{} {
+	return description(d.def.Description())
+}
+
+func (d *introspectedDirective) Locations() []string {
+	var list []string
+	for _, l := range d.def.Locations() {
+		list = append(list, string(l))
+	}
+	return list
+}
+
+func (d *introspectedDirective) Args() []*introspectedInputValue {
+	return d.schema.arguments(d.def.Arguments())
+}
+
+func (d *introspectedDirective) IsRepeatable() bool {
+	return d.def.IsRepeatable()
+}
@@ -0,0 +1,260 @@
+package execute
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	argsType    = reflect.TypeOf(map[string]interface{}(nil))
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// accessor describes how to get to the value of a single GraphQL field
+// from a Go value. It is either a struct field or a method
+type accessor struct {
+	index    []int // struct field index. nil for methods
+	method   int   // method index. only valid when index is nil
+	wantCtx  bool  // method takes a context.Context
+	wantArgs bool  // method takes the arguments map
+	hasError bool  // method returns an error as the last value
+}
+
+// typeInfo is the per-type metadata that is cached so that we do not
+// need to walk the Go type every time a field is resolved
+type typeInfo struct {
+	tagged map[string]*accessor // keyed by `graphql:"name"`
+	folded map[string]*accessor // keyed by lower cased Go name
+}
+
+var typeInfoCache = struct {
+	sync.RWMutex
+	types map[reflect.Type]*typeInfo
+}{
+	types: make(map[reflect.Type]*typeInfo),
+}
+
+// DefaultResolveField is the resolver that is used for fields that
+// do not have a resolver of their own. It looks for the field `name`
+// in `source` in the following order:
+//
+// * If source is a map with string keys, the value for key `name`
+// * A struct field with a `graphql:"name"` tag
+// * An exported method whose name matches `name`, ignoring case. The method
+//   may optionally take a context.Context and/or a map[string]interface{}
+//   of arguments, and may optionally return an error as its last value
+// * An exported struct field whose name matches `name`, ignoring case
+//
+// If nothing matches, nil is returned.
+func DefaultResolveField(ctx context.Context, name string, source interface{}, args map[string]interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, nil
+		}
+		v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, nil
+		}
+		return v.Interface(), nil
+	case reflect.Struct:
+		// make an addressable copy, so that methods with pointer
+		// receivers can also be used
+		ptr := reflect.New(rv.Type())
+		ptr.Elem().Set(rv)
+		rv = ptr
+	case reflect.Ptr:
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	info := lookupTypeInfo(rv.Type())
+	a, ok := info.tagged[name]
+	if !ok {
+		a, ok = info.folded[strings.ToLower(name)]
+		if !ok {
+			return nil, nil
+		}
+	}
+
+	if a.index != nil {
+		return fieldByIndex(rv.Elem(), a.index), nil
+	}
+	return callMethod(ctx, rv.Method(a.method), a, args)
+}
+
+func fieldByIndex(rv reflect.Value, index []int) interface{} {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv.Interface()
+}
+
+func callMethod(ctx context.Context, m reflect.Value, a *accessor, args map[string]interface{}) (interface{}, error) {
+	var in []reflect.Value
+	if a.wantCtx {
+		in = append(in, reflect.ValueOf(&ctx).Elem())
+	}
+	if a.wantArgs {
+		in = append(in, reflect.ValueOf(args))
+	}
+
+	out := m.Call(in)
+	if a.hasError {
+		if err, _ := out[len(out)-1].Interface().(error); err != nil {
+			return nil, err
+		}
+		if len(out) == 1 {
+			return nil, nil
+		}
+	}
+	return out[0].Interface(), nil
+}
+
+func lookupTypeInfo(t reflect.Type) *typeInfo {
+	typeInfoCache.RLock()
+	info, ok := typeInfoCache.types[t]
+	typeInfoCache.RUnlock()
+	if ok {
+		return info
+	}
+
+	info = buildTypeInfo(t)
+
+	typeInfoCache.Lock()
+	typeInfoCache.types[t] = info
+	typeInfoCache.Unlock()
+	return info
+}
+
+// buildTypeInfo builds the metadata for `t`, which must be a pointer
+// to a struct
+func buildTypeInfo(t reflect.Type) *typeInfo {
+	info := &typeInfo{
+		tagged: make(map[string]*accessor),
+		folded: make(map[string]*accessor),
+	}
+
+	collectStructFields(info, t.Elem(), nil, make(map[reflect.Type]struct{}))
+
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if m.PkgPath != "" { // unexported
+			continue
+		}
+
+		a, err := methodAccessor(m.Type)
+		if err != nil {
+			continue
+		}
+		a.method = i
+		info.folded[strings.ToLower(m.Name)] = a
+	}
+	return info
+}
+
+func collectStructFields(info *typeInfo, t reflect.Type, parent []int, seen map[reflect.Type]struct{}) {
+	if _, ok := seen[t]; ok {
+		return
+	}
+	seen[t] = struct{}{}
+
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+		f.Index = index
+
+		if f.Anonymous {
+			embedded = append(embedded, f)
+			continue
+		}
+
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		a := &accessor{index: f.Index}
+		if tag := f.Tag.Get("graphql"); tag != "" {
+			name := strings.SplitN(tag, ",", 2)[0]
+			if name == "-" {
+				continue
+			}
+			if name != "" {
+				if _, ok := info.tagged[name]; !ok {
+					info.tagged[name] = a
+				}
+				continue
+			}
+		}
+
+		key := strings.ToLower(f.Name)
+		if _, ok := info.folded[key]; !ok {
+			info.folded[key] = a
+		}
+	}
+
+	// fields in embedded structs are shadowed by those in the outer struct
+	for _, f := range embedded {
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct {
+			continue
+		}
+		collectStructFields(info, ft, f.Index, seen)
+	}
+}
+
+// methodAccessor checks that the method type (which includes the
+// receiver) is usable as a field resolver
+func methodAccessor(t reflect.Type) (*accessor, error) {
+	var a accessor
+	in := 1 // skip the receiver
+	if in < t.NumIn() && t.In(in) == contextType {
+		a.wantCtx = true
+		in++
+	}
+	if in < t.NumIn() && t.In(in) == argsType {
+		a.wantArgs = true
+		in++
+	}
+	if in != t.NumIn() {
+		return nil, errors.New(`unsupported method arguments`)
+	}
+
+	switch t.NumOut() {
+	case 1:
+		a.hasError = t.Out(0) == errorType
+	case 2:
+		if t.Out(1) != errorType {
+			return nil, errors.New(`second return value must be an error`)
+		}
+		a.hasError = true
+	default:
+		return nil, errors.New(`unsupported number of return values`)
+	}
+	return &a, nil
+}
@@ -0,0 +1,83 @@
+package execute
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Result is the outcome of executing an operation. It serializes to
+// the standard `{"data": ..., "errors": [...]}` response shape
+type Result struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error describes a problem that occurred while resolving a field.
+// Path points to the response key (or list index) that caused it
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+// OrderedMap is a map that remembers the order in which its keys
+// were set. Execution uses it so that the response lists fields in
+// the same order as they were requested
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{
+		values: make(map[string]interface{}),
+	}
+}
+
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to marshal key`)
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to marshal value for key %s`, key)
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
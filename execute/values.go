@@ -0,0 +1,360 @@
+package execute
+
+import (
+	"reflect"
+
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/pkg/errors"
+)
+
+func (ctx *execCtx) coerceVariableValues(op model.OperationDefinition, provided map[string]interface{}) error {
+	ctx.variables = make(map[string]interface{})
+	for vdef := range op.Variables() {
+		v, ok := provided[vdef.Name()]
+		if !ok {
+			if vdef.HasDefaultValue() {
+				dv, err := ctx.coerceLiteral(vdef.Type(), vdef.DefaultValue())
+				if err != nil {
+					return errors.Wrapf(err, `failed to coerce default value for variable $%s`, vdef.Name())
+				}
+				ctx.variables[vdef.Name()] = dv
+				continue
+			}
+
+			if !model.IsNullable(vdef.Type()) {
+				return errors.Errorf(`variable $%s of required type %s was not provided`, vdef.Name(), model.TypeString(vdef.Type()))
+			}
+			continue
+		}
+
+		if v == nil && !model.IsNullable(vdef.Type()) {
+			return errors.Errorf(`variable $%s of required type %s must not be null`, vdef.Name(), model.TypeString(vdef.Type()))
+		}
+
+		cv, err := ctx.coerceValue(vdef.Type(), v)
+		if err != nil {
+			return errors.Wrapf(err, `failed to coerce variable $%s`, vdef.Name())
+		}
+		ctx.variables[vdef.Name()] = cv
+	}
+	return nil
+}
+
+// coerceValue converts a variable value provided by the caller into
+// its Go representation, according to the declared input type `typ`.
+// Input objects must be given as map[string]interface{}, and receive
+// the default values of the fields that are missing. Values of enum
+// types must be given as the names of their elements. Values of
+// unknown types are used as is
+func (ctx *execCtx) coerceValue(typ model.Type, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if lt, ok := typ.(model.ListType); ok {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			// A single value is accepted where a list is expected
+			item, err := ctx.coerceValue(lt.Type(), v)
+			if err != nil {
+				return nil, err
+			}
+			if item == nil && !model.IsNullable(lt.Type()) {
+				return nil, errors.Errorf(`list element #0 of required type %s must not be null`, model.TypeString(lt.Type()))
+			}
+			return []interface{}{item}, nil
+		}
+
+		list := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item, err := ctx.coerceValue(lt.Type(), rv.Index(i).Interface())
+			if err != nil {
+				return nil, errors.Wrapf(err, `failed to coerce list element #%d`, i)
+			}
+			if item == nil && !model.IsNullable(lt.Type()) {
+				return nil, errors.Errorf(`list element #%d of required type %s must not be null`, i, model.TypeString(lt.Type()))
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	}
+
+	name := model.TypeName(typ)
+	def, ok := ctx.lookupType(name)
+	if !ok {
+		return v, nil
+	}
+
+	switch def.(type) {
+	case model.ScalarDefinition:
+		cv, err := def.(model.ScalarDefinition).ParseValue(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, `invalid value for type %s`, name)
+		}
+		return cv, nil
+	case model.EnumDefinition:
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.Errorf(`expected enum value for type %s, got %T`, name, v)
+		}
+		return coerceEnum(def.(model.EnumDefinition), s)
+	case model.InputDefinition:
+		provided, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf(`expected object value for type %s, got %T`, name, v)
+		}
+
+		fields := make(map[string]model.InputFieldDefinition)
+		for f := range def.(model.InputDefinition).Fields() {
+			fields[f.Name()] = f
+		}
+
+		m := make(map[string]interface{})
+		for fname, fv := range provided {
+			f, ok := fields[fname]
+			if !ok {
+				return nil, errors.Errorf(`field "%s" is not defined by type %s`, fname, name)
+			}
+			cv, err := ctx.coerceValue(f.Type(), fv)
+			if err != nil {
+				return nil, errors.Wrapf(err, `failed to coerce field "%s"`, fname)
+			}
+			if cv == nil && !model.IsNullable(f.Type()) {
+				return nil, errors.Errorf(`field "%s" of required type %s must not be null`, fname, model.TypeString(f.Type()))
+			}
+			m[fname] = cv
+		}
+
+		if err := ctx.applyInputDefaults(def.(model.InputDefinition), m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	return v, nil
+}
+
+// applyInputDefaults sets the default values of the fields of the
+// input type `def` that are missing from `m`. An error is returned if
+// a field of non-null type without a default value is missing
+func (ctx *execCtx) applyInputDefaults(def model.InputDefinition, m map[string]interface{}) error {
+	for f := range def.Fields() {
+		if _, ok := m[f.Name()]; ok {
+			continue
+		}
+
+		if f.HasDefaultValue() {
+			dv, err := ctx.coerceLiteral(f.Type(), f.DefaultValue())
+			if err != nil {
+				return errors.Wrapf(err, `failed to coerce default value for field "%s"`, f.Name())
+			}
+			m[f.Name()] = dv
+			continue
+		}
+
+		if !model.IsNullable(f.Type()) {
+			return errors.Errorf(`field "%s" of required type %s was not provided`, f.Name(), model.TypeString(f.Type()))
+		}
+	}
+	return nil
+}
+
+func (ctx *execCtx) coerceArgumentValues(fdef model.ObjectFieldDefinition, field model.SelectionField) (map[string]interface{}, error) {
+	provided := make(map[string]model.Value)
+	for arg := range field.Arguments() {
+		provided[arg.Name()] = arg.Value()
+	}
+
+	args := make(map[string]interface{})
+	for adef := range fdef.Arguments() {
+		v, ok := provided[adef.Name()]
+		if ok && v.Kind() == model.VariableKind {
+			if _, exists := ctx.variables[v.Value().(string)]; !exists {
+				ok = false
+			}
+		}
+
+		if !ok {
+			if adef.HasDefaultValue() {
+				dv, err := ctx.coerceLiteral(adef.Type(), adef.DefaultValue())
+				if err != nil {
+					return nil, errors.Wrapf(err, `failed to coerce default value for argument "%s"`, adef.Name())
+				}
+				args[adef.Name()] = dv
+				continue
+			}
+
+			if !model.IsNullable(adef.Type()) {
+				return nil, errors.Errorf(`argument "%s" of required type %s was not provided`, adef.Name(), model.TypeString(adef.Type()))
+			}
+			continue
+		}
+
+		cv, err := ctx.coerceLiteral(adef.Type(), v)
+		if err != nil {
+			return nil, errors.Wrapf(err, `failed to coerce argument "%s"`, adef.Name())
+		}
+
+		if cv == nil && !model.IsNullable(adef.Type()) {
+			return nil, errors.Errorf(`argument "%s" of required type %s must not be null`, adef.Name(), model.TypeString(adef.Type()))
+		}
+		args[adef.Name()] = cv
+	}
+	return args, nil
+}
+
+// coerceLiteral converts a literal value found in the document into
+// its Go representation, according to the declared input type `typ`
+func (ctx *execCtx) coerceLiteral(typ model.Type, v model.Value) (interface{}, error) {
+	switch v.Kind() {
+	case model.VariableKind:
+		return ctx.variables[v.Value().(string)], nil
+	case model.NullKind:
+		return nil, nil
+	}
+
+	if lt, ok := typ.(model.ListType); ok {
+		if v.Kind() == model.ListKind {
+			list := []interface{}{}
+			for elem := range v.(model.ListValue).Values() {
+				item, err := ctx.coerceLiteral(lt.Type(), elem)
+				if err != nil {
+					return nil, errors.Wrapf(err, `failed to coerce list element #%d`, len(list))
+				}
+				if item == nil && !model.IsNullable(lt.Type()) {
+					return nil, errors.Errorf(`list element #%d of required type %s must not be null`, len(list), model.TypeString(lt.Type()))
+				}
+				list = append(list, item)
+			}
+			return list, nil
+		}
+
+		// A single value is accepted where a list is expected
+		item, err := ctx.coerceLiteral(lt.Type(), v)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil && !model.IsNullable(lt.Type()) {
+			return nil, errors.Errorf(`list element #0 of required type %s must not be null`, model.TypeString(lt.Type()))
+		}
+		return []interface{}{item}, nil
+	}
+
+	name := model.TypeName(typ)
+	def, ok := ctx.lookupType(name)
+	if !ok {
+		return ctx.literalValue(v)
+	}
+
+	switch def.(type) {
+	case model.ScalarDefinition:
+		cv, err := def.(model.ScalarDefinition).ParseLiteral(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, `invalid value for type %s`, name)
+		}
+		return cv, nil
+	case model.EnumDefinition:
+		if v.Kind() != model.EnumKind {
+			return nil, errors.Errorf(`expected enum value for type %s, got %s`, name, v.Kind())
+		}
+		return coerceEnum(def.(model.EnumDefinition), v.Value().(string))
+	case model.InputDefinition:
+		if v.Kind() != model.ObjectKind {
+			return nil, errors.Errorf(`expected object value for type %s, got %s`, name, v.Kind())
+		}
+
+		fields := make(map[string]model.InputFieldDefinition)
+		for f := range def.(model.InputDefinition).Fields() {
+			fields[f.Name()] = f
+		}
+
+		m := make(map[string]interface{})
+		for of := range v.(model.ObjectValue).Fields() {
+			f, ok := fields[of.Name()]
+			if !ok {
+				return nil, errors.Errorf(`field "%s" is not defined by type %s`, of.Name(), name)
+			}
+
+			// Fields given variables that were not provided are
+			// treated as if they were not given at all
+			fv := of.Value()
+			if fv.Kind() == model.VariableKind {
+				if _, exists := ctx.variables[fv.Value().(string)]; !exists {
+					continue
+				}
+			}
+
+			cv, err := ctx.coerceLiteral(f.Type(), fv)
+			if err != nil {
+				return nil, errors.Wrapf(err, `failed to coerce field "%s"`, of.Name())
+			}
+			if cv == nil && !model.IsNullable(f.Type()) {
+				return nil, errors.Errorf(`field "%s" of required type %s must not be null`, of.Name(), model.TypeString(f.Type()))
+			}
+			m[of.Name()] = cv
+		}
+
+		if err := ctx.applyInputDefaults(def.(model.InputDefinition), m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	default:
+		return nil, errors.Errorf(`type %s can not be used as an input type`, name)
+	}
+}
+
+// literalValue converts a literal value into its Go representation
+// without any knowledge of the expected type
+func (ctx *execCtx) literalValue(v model.Value) (interface{}, error) {
+	switch v.Kind() {
+	case model.VariableKind:
+		return ctx.variables[v.Value().(string)], nil
+	case model.ObjectKind:
+		m := make(map[string]interface{})
+		for f := range v.(model.ObjectValue).Fields() {
+			fv, err := ctx.literalValue(f.Value())
+			if err != nil {
+				return nil, errors.Wrapf(err, `failed to convert field "%s"`, f.Name())
+			}
+			m[f.Name()] = fv
+		}
+		return m, nil
+	case model.ListKind:
+		list := []interface{}{}
+		for elem := range v.(model.ListValue).Values() {
+			ev, err := ctx.literalValue(elem)
+			if err != nil {
+				return nil, errors.Wrapf(err, `failed to convert list element #%d`, len(list))
+			}
+			list = append(list, ev)
+		}
+		return list, nil
+	default:
+		return v.Value(), nil
+	}
+}
+
+// coerceEnum returns the value of the element of `def` named `name`
+func coerceEnum(def model.EnumDefinition, name string) (interface{}, error) {
+	for e := range def.Elements() {
+		if e.Name() != name {
+			continue
+		}
+		if ev := e.Value(); ev != nil {
+			return ev.Value(), nil
+		}
+		return e.Name(), nil
+	}
+	return nil, errors.Errorf(`invalid value %s for enum %s`, name, def.Name())
+}
+
+func serializeEnum(def model.EnumDefinition, v interface{}) (interface{}, error) {
+	for e := range def.Elements() {
+		if s, ok := v.(string); ok && s == e.Name() {
+			return e.Name(), nil
+		}
+		if ev := e.Value(); ev != nil && reflect.DeepEqual(ev.Value(), v) {
+			return e.Name(), nil
+		}
+	}
+	return nil, errors.Errorf(`invalid value %v for enum %s`, v, def.Name())
+}
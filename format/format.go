@@ -36,8 +36,6 @@ var fmtHandler = &visitor.Handler{
 	EnterInlineFragment:           enterInlineFragment,
 	EnterFragmentSpread:           enterFragmentSpread,
 	EnterFragmentDefinition:       enterFragmentDefinition,
-	EnterDirective:                enterDirective,
-	EnterDirectiveList:            enterDirectiveList,
 	EnterUnionDefinition:          enterUnionDefinition,
 	EnterInterfaceDefinition:      enterInterfaceDefinition,
 	LeaveInterfaceDefinition:      leaveInterfaceDefinition,
@@ -46,7 +44,19 @@ var fmtHandler = &visitor.Handler{
 	LeaveObjectDefinition:         leaveObjectDefinition,
 	EnterObjectFieldDefinition:    enterObjectFieldDefinition,
 	EnterEnumDefinition:           enterEnumDefinition,
+	EnterScalarDefinition:         enterScalarDefinition,
+	EnterDirectiveDefinition:      enterDirectiveDefinition,
 	EnterSchema:                   enterSchema,
+	EnterObjectExtension:          enterObjectExtension,
+	LeaveObjectExtension:          leaveObjectExtension,
+	EnterInterfaceExtension:       enterInterfaceExtension,
+	LeaveInterfaceExtension:       leaveInterfaceExtension,
+	EnterUnionExtension:           enterUnionExtension,
+	EnterEnumExtension:            enterEnumExtension,
+	EnterInputExtension:           enterInputExtension,
+	LeaveInputExtension:           leaveInputExtension,
+	EnterScalarExtension:          enterScalarExtension,
+	EnterSchemaExtension:          enterSchemaExtension,
 }
 
 const singleindent = "  "
@@ -114,6 +124,9 @@ func enterOperationDefinition(c context.Context, v model.OperationDefinition) er
 					return errors.Wrap(err, `failed to format default value`)
 				}
 			}
+			if err := fmtDirectives(ctx, vardef.Directives()); err != nil {
+				return errors.Wrap(err, `failed to format directives`)
+			}
 			if l-1 > i {
 				buf.WriteString(", ")
 			}
@@ -121,6 +134,10 @@ func enterOperationDefinition(c context.Context, v model.OperationDefinition) er
 		}
 		buf.WriteByte(')')
 	}
+
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	ctx.padSelectionList = true
 	return nil
 }
@@ -176,6 +193,10 @@ func enterSelectionField(c context.Context, v model.SelectionField) error {
 		return errors.Wrap(err, `failed to format arguments`)
 	}
 
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+
 	ctx.padSelectionList = true
 	return nil
 }
@@ -183,13 +204,18 @@ func enterSelectionField(c context.Context, v model.SelectionField) error {
 func enterInlineFragment(c context.Context, v model.InlineFragment) error {
 	ctx := c.(*fmtCtx)
 	buf := ctx.buf
-	buf.WriteString("... ")
+	buf.WriteString("...")
 
 	if typ := v.TypeCondition(); typ != nil {
+		buf.WriteByte(' ')
 		if err := fmtTypeCondition(ctx, typ); err != nil {
 			return errors.Wrap(err, `failed to format type condition`)
 		}
 	}
+
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	ctx.padSelectionList = true
 	return nil
 }
@@ -199,6 +225,10 @@ func enterFragmentSpread(c context.Context, v model.FragmentSpread) error {
 	buf := ctx.buf
 	buf.WriteString("...")
 	buf.WriteString(v.Name())
+
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	return nil
 }
 
@@ -212,28 +242,25 @@ func enterFragmentDefinition(c context.Context, v model.FragmentDefinition) erro
 	if err := fmtTypeCondition(ctx, v.Type().(model.NamedType)); err != nil {
 		return errors.Wrap(err, `failed to format type condition`)
 	}
-	ctx.padSelectionList = true
-	return nil
-}
 
-func enterDirectiveList(c context.Context) error {
-	ctx := c.(*fmtCtx)
-	buf := ctx.buf
-	buf.WriteByte(' ')
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	ctx.padSelectionList = true
 	return nil
 }
 
 func enterSchema(c context.Context, v model.Schema) error {
 	ctx := c.(*fmtCtx)
 	buf := ctx.buf
-
-	buf.WriteString("schema {")
+	fmtDescription(ctx, v)
+	buf.WriteString("schema")
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	buf.WriteString(" {")
 	moreIndent(c)
-
-	buf.WriteByte('\n')
-	buf.Write(ctx.indentbuf)
-	buf.WriteString("query: ")
-	buf.WriteString(v.Query().Name())
+	fmtRootOperationTypes(ctx, v.Query(), v.Mutation(), v.Subscription())
 
 	if ch := v.Types(); len(ch) > 0 {
 		buf.WriteByte('\n')
@@ -250,31 +277,44 @@ func enterSchema(c context.Context, v model.Schema) error {
 		}
 		buf.WriteByte(']')
 	}
+	lessIndent(c)
 	buf.WriteByte('\n')
 	buf.WriteByte('}')
 	return nil
 }
 
-func enterDirective(c context.Context, v model.Directive) error {
-	ctx := c.(*fmtCtx)
+// fmtRootOperationTypes writes the root operation types of a schema
+// definition or extension, one per line. Types that are nil are skipped
+func fmtRootOperationTypes(ctx *fmtCtx, query, mutation, subscription model.NamedType) {
 	buf := ctx.buf
-	if ctx.elements[ctx.element] > 0 {
-		ctx.buf.WriteByte('0')
-	}
-
-	buf.WriteByte('@')
-	buf.WriteString(v.Name())
-	if err := fmtArgumentList(ctx, v.Arguments()); err != nil {
-		return errors.Wrap(err, `failed to format arguments`)
+	for _, root := range []struct {
+		key string
+		typ model.NamedType
+	}{
+		{"query", query},
+		{"mutation", mutation},
+		{"subscription", subscription},
+	} {
+		if root.typ == nil {
+			continue
+		}
+		buf.WriteByte('\n')
+		buf.Write(ctx.indentbuf)
+		buf.WriteString(root.key)
+		buf.WriteString(": ")
+		buf.WriteString(root.typ.Name())
 	}
-	return nil
 }
 
 func enterInputDefinition(c context.Context, v model.InputDefinition) error {
 	ctx := c.(*fmtCtx)
 	buf := ctx.buf
+	fmtDescription(ctx, v)
 	buf.WriteString("input ")
 	buf.WriteString(v.Name())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	buf.WriteString(" {")
 	moreIndent(c)
 	return enterList(c)
@@ -300,11 +340,21 @@ func enterInputFieldDefinition(c context.Context, v model.InputFieldDefinition)
 
 	buf.WriteByte('\n')
 	buf.Write(ctx.indentbuf)
+	fmtDescription(ctx, v)
 	buf.WriteString(v.Name())
 	buf.WriteString(": ")
 	if err := fmtType(ctx, v.Type()); err != nil {
 		return errors.Wrap(err, `failed to format field type`)
 	}
+	if v.HasDefaultValue() {
+		buf.WriteString(" = ")
+		if err := fmtValue(ctx, v.DefaultValue()); err != nil {
+			return errors.Wrap(err, `failed to format default value`)
+		}
+	}
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	return nil
 }
 
@@ -312,14 +362,25 @@ func enterUnionDefinition(c context.Context, v model.UnionDefinition) error {
 	ctx := c.(*fmtCtx)
 	buf := ctx.buf
 
+	fmtDescription(ctx, v)
 	buf.WriteString("union ")
 	buf.WriteString(v.Name())
-	buf.WriteString(" = ")
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 
 	ch := v.Types()
 	if len(ch) == 0 {
 		return errors.New(`union without any types to compose is meaningless`)
 	}
+	return fmtUnionMemberTypes(ctx, ch)
+}
+
+// fmtUnionMemberTypes writes the member types of a union, including
+// the leading =
+func fmtUnionMemberTypes(ctx *fmtCtx, ch chan model.Type) error {
+	buf := ctx.buf
+	buf.WriteString(" = ")
 
 	// write the first one
 	t := <-ch
@@ -344,8 +405,13 @@ func enterInterfaceDefinition(c context.Context, v model.InterfaceDefinition) er
 	ctx := c.(*fmtCtx)
 	buf := ctx.buf
 
+	fmtDescription(ctx, v)
 	buf.WriteString("interface ")
 	buf.WriteString(v.Name())
+	fmtImplements(ctx, v.Interfaces())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	buf.WriteString(" {")
 	moreIndent(c)
 	return nil
@@ -369,11 +435,18 @@ func enterInterfaceFieldDefinition(c context.Context, v model.InterfaceFieldDefi
 
 	buf.WriteByte('\n')
 	buf.Write(ctx.indentbuf)
+	fmtDescription(ctx, v)
 	buf.WriteString(v.Name())
+	if err := fmtObjectFieldArgumentDefinitionList(ctx, v.Arguments()); err != nil {
+		return errors.Wrap(err, `failed to format interface field arguments`)
+	}
 	buf.WriteString(": ")
 	if err := fmtType(ctx, v.Type()); err != nil {
 		return errors.Wrap(err, `failed to format field type`)
 	}
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	return nil
 }
 
@@ -381,11 +454,12 @@ func enterObjectDefinition(c context.Context, v model.ObjectDefinition) error {
 	ctx := c.(*fmtCtx)
 	buf := ctx.buf
 
+	fmtDescription(ctx, v)
 	buf.WriteString("type ")
 	buf.WriteString(v.Name())
-	if v.HasImplements() {
-		buf.WriteString(" implements ")
-		buf.WriteString(v.Implements().(model.NamedType).Name())
+	fmtImplements(ctx, v.Interfaces())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
 	}
 	buf.WriteString(" {")
 	moreIndent(c)
@@ -410,6 +484,7 @@ func enterObjectFieldDefinition(c context.Context, v model.ObjectFieldDefinition
 
 	buf.WriteByte('\n')
 	buf.Write(ctx.indentbuf)
+	fmtDescription(ctx, v)
 	buf.WriteString(v.Name())
 	if err := fmtObjectFieldArgumentDefinitionList(ctx, v.Arguments()); err != nil {
 		return errors.Wrap(err, `failed to format object field argumets`)
@@ -418,6 +493,9 @@ func enterObjectFieldDefinition(c context.Context, v model.ObjectFieldDefinition
 	if err := fmtType(ctx, v.Type()); err != nil {
 		return errors.Wrap(err, `failed to format object field type`)
 	}
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	return nil
 }
 
@@ -425,22 +503,222 @@ func enterEnumDefinition(c context.Context, v model.EnumDefinition) error {
 	ctx := c.(*fmtCtx)
 	buf := ctx.buf
 
+	fmtDescription(ctx, v)
 	buf.WriteString("enum ")
 	buf.WriteString(v.Name())
-	buf.WriteString(" {")
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	ch := v.Elements()
 	if len(ch) == 0 {
 		return errors.New(`enum without any elements to compose is meaningless`)
 	}
+	return fmtEnumElementDefinitions(ctx, ch)
+}
 
-	moreIndent(c)
+// fmtEnumElementDefinitions writes the values of an enum, including
+// the surrounding braces
+func fmtEnumElementDefinitions(ctx *fmtCtx, ch chan model.EnumElementDefinition) error {
+	buf := ctx.buf
+	buf.WriteString(" {")
+	moreIndent(ctx)
 	for e := range ch {
 		buf.WriteByte('\n')
 		buf.Write(ctx.indentbuf)
+		fmtDescription(ctx, e)
 		buf.WriteString(e.Name())
+		if err := fmtDirectives(ctx, e.Directives()); err != nil {
+			return errors.Wrap(err, `failed to format directives`)
+		}
+	}
+	lessIndent(ctx)
+	buf.WriteByte('\n')
+	buf.Write(ctx.indentbuf)
+	buf.WriteByte('}')
+	return nil
+}
+
+func enterScalarDefinition(c context.Context, v model.ScalarDefinition) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	fmtDescription(ctx, v)
+	buf.WriteString("scalar ")
+	buf.WriteString(v.Name())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	return nil
+}
+
+func enterDirectiveDefinition(c context.Context, v model.DirectiveDefinition) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	locations := v.Locations()
+	if len(locations) == 0 {
+		return errors.New(`directive without any locations is meaningless`)
+	}
+
+	fmtDescription(ctx, v)
+	buf.WriteString("directive @")
+	buf.WriteString(v.Name())
+	if err := fmtObjectFieldArgumentDefinitionList(ctx, v.Arguments()); err != nil {
+		return errors.Wrap(err, `failed to format directive arguments`)
 	}
+	if v.IsRepeatable() {
+		buf.WriteString(" repeatable")
+	}
+	buf.WriteString(" on ")
+	for i, l := range locations {
+		if i > 0 {
+			buf.WriteString(" | ")
+		}
+		buf.WriteString(string(l))
+	}
+	return nil
+}
+
+// closeBlock ends a block of fields opened with " {"
+func closeBlock(c context.Context) {
 	lessIndent(c)
-	buf.WriteString("\n}")
+
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+	buf.WriteByte('\n')
+	buf.Write(ctx.indentbuf)
+	buf.WriteByte('}')
+}
+
+func enterObjectExtension(c context.Context, v model.ObjectExtension) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	buf.WriteString("extend type ")
+	buf.WriteString(v.Name())
+	fmtImplements(ctx, v.Interfaces())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	if len(v.Fields()) > 0 {
+		buf.WriteString(" {")
+		moreIndent(c)
+	}
+	return nil
+}
+
+func leaveObjectExtension(c context.Context, v model.ObjectExtension) error {
+	if len(v.Fields()) == 0 {
+		return nil
+	}
+	closeBlock(c)
+	return nil
+}
+
+func enterInterfaceExtension(c context.Context, v model.InterfaceExtension) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	buf.WriteString("extend interface ")
+	buf.WriteString(v.Name())
+	fmtImplements(ctx, v.Interfaces())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	if len(v.Fields()) > 0 {
+		buf.WriteString(" {")
+		moreIndent(c)
+	}
+	return nil
+}
+
+func leaveInterfaceExtension(c context.Context, v model.InterfaceExtension) error {
+	if len(v.Fields()) == 0 {
+		return nil
+	}
+	closeBlock(c)
+	return nil
+}
+
+func enterUnionExtension(c context.Context, v model.UnionExtension) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	buf.WriteString("extend union ")
+	buf.WriteString(v.Name())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	if ch := v.Types(); len(ch) > 0 {
+		return fmtUnionMemberTypes(ctx, ch)
+	}
+	return nil
+}
+
+func enterEnumExtension(c context.Context, v model.EnumExtension) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	buf.WriteString("extend enum ")
+	buf.WriteString(v.Name())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	if ch := v.Elements(); len(ch) > 0 {
+		return fmtEnumElementDefinitions(ctx, ch)
+	}
+	return nil
+}
+
+func enterInputExtension(c context.Context, v model.InputExtension) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	buf.WriteString("extend input ")
+	buf.WriteString(v.Name())
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	if len(v.Fields()) > 0 {
+		buf.WriteString(" {")
+		moreIndent(c)
+	}
+	return nil
+}
+
+func leaveInputExtension(c context.Context, v model.InputExtension) error {
+	if len(v.Fields()) == 0 {
+		return nil
+	}
+	closeBlock(c)
+	return nil
+}
+
+func enterScalarExtension(c context.Context, v model.ScalarExtension) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	buf.WriteString("extend scalar ")
+	buf.WriteString(v.Name())
+	return fmtDirectives(ctx, v.Directives())
+}
+
+func enterSchemaExtension(c context.Context, v model.SchemaExtension) error {
+	ctx := c.(*fmtCtx)
+	buf := ctx.buf
+
+	buf.WriteString("extend schema")
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
+	if v.Query() == nil && v.Mutation() == nil && v.Subscription() == nil {
+		return nil
+	}
+
+	buf.WriteString(" {")
+	moreIndent(c)
+	fmtRootOperationTypes(ctx, v.Query(), v.Mutation(), v.Subscription())
+	closeBlock(c)
 	return nil
 }
 
@@ -480,6 +758,50 @@ func (ctx *fmtCtx) leave() {
 	}
 }
 
+// fmtDescription writes the description of `v` if there is one,
+// followed by a new line and the current indentation
+func fmtDescription(ctx *fmtCtx, v model.Describer) {
+	description := v.Description()
+	if description == "" {
+		return
+	}
+
+	buf := ctx.buf
+	fmtString(ctx, description)
+	buf.WriteByte('\n')
+	buf.Write(ctx.indentbuf)
+}
+
+// fmtDirectives writes the directives in `ch`, each one preceded by
+// a space
+func fmtDirectives(ctx *fmtCtx, ch chan model.Directive) error {
+	buf := ctx.buf
+	for d := range ch {
+		buf.WriteString(" @")
+		buf.WriteString(d.Name())
+		if err := fmtArgumentList(ctx, d.Arguments()); err != nil {
+			return errors.Wrap(err, `failed to format directive arguments`)
+		}
+	}
+	return nil
+}
+
+// fmtImplements writes the implements clause for the interfaces in
+// `ch`, if there are any
+func fmtImplements(ctx *fmtCtx, ch chan model.NamedType) {
+	buf := ctx.buf
+	i := 0
+	for typ := range ch {
+		if i == 0 {
+			buf.WriteString(" implements ")
+		} else {
+			buf.WriteString(" & ")
+		}
+		buf.WriteString(typ.Name())
+		i++
+	}
+}
+
 func fmtTypeCondition(ctx *fmtCtx, typ model.NamedType) error {
 	buf := ctx.buf
 	buf.WriteString("on ")
@@ -532,12 +854,27 @@ func fmtValue(ctx *fmtCtx, v model.Value) error {
 		buf.WriteString(strconv.Itoa(v.Value().(int)))
 	case model.FloatKind:
 		buf.WriteString(strconv.FormatFloat(v.Value().(float64), 'g', -1, 64))
-	case model.StringKind, model.EnumKind:
+	case model.StringKind:
+		fmtString(ctx, v.Value().(string))
+	case model.EnumKind:
 		buf.WriteString(v.Value().(string))
 	case model.BooleanKind:
 		buf.WriteString(strconv.FormatBool(v.Value().(bool)))
 	case model.NullKind:
 		buf.WriteString("null")
+	case model.ListKind:
+		buf.WriteByte('[')
+		i := 0
+		for elem := range v.(model.ListValue).Values() {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := fmtValue(ctx, elem); err != nil {
+				return errors.Wrap(err, `failed to format list element`)
+			}
+			i++
+		}
+		buf.WriteByte(']')
 	case model.ObjectKind:
 		buf.WriteByte('{')
 		moreIndent(ctx)
@@ -612,6 +949,9 @@ func fmtObjectFieldArgumentDefinition(ctx *fmtCtx, v model.ObjectFieldArgumentDe
 			return errors.Wrap(err, `failed to format default value`)
 		}
 	}
+	if err := fmtDirectives(ctx, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to format directives`)
+	}
 	return nil
 }
 
@@ -621,18 +961,40 @@ func fmtObjectFieldArgumentDefinitionList(ctx *fmtCtx, argch chan model.ObjectFi
 		return nil
 	}
 
+	// arguments are written one per line when any of them has
+	// a description
+	args := make([]model.ObjectFieldArgumentDefinition, 0, l)
+	multiline := false
+	for arg := range argch {
+		if arg.Description() != "" {
+			multiline = true
+		}
+		args = append(args, arg)
+	}
+
 	buf := ctx.buf
 	buf.WriteByte('(')
+	if multiline {
+		moreIndent(ctx)
+	}
 
-	argc := 0
-	for arg := range argch {
+	for argc, arg := range args {
+		if multiline {
+			buf.WriteByte('\n')
+			buf.Write(ctx.indentbuf)
+			fmtDescription(ctx, arg)
+		} else if argc > 0 {
+			buf.WriteString(", ")
+		}
 		if err := fmtObjectFieldArgumentDefinition(ctx, arg); err != nil {
 			return errors.Wrap(err, `failed to format argument`)
 		}
-		if l-1 > argc {
-			buf.WriteString(", ")
-		}
-		argc++
+	}
+
+	if multiline {
+		lessIndent(ctx)
+		buf.WriteByte('\n')
+		buf.Write(ctx.indentbuf)
 	}
 	buf.WriteByte(')')
 
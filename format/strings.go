@@ -0,0 +1,109 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+const hexdigits = "0123456789abcdef"
+
+// Quote returns `s` as a GraphQL string literal, surrounded by double
+// quotes and with the characters that can not appear literally escaped
+func Quote(s string) string {
+	var buf bytes.Buffer
+	writeQuoted(&buf, s)
+	return buf.String()
+}
+
+func writeQuoted(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f || r == utf8.RuneError {
+				buf.WriteString(`\u`)
+				for shift := uint(12); ; shift -= 4 {
+					buf.WriteByte(hexdigits[(r>>shift)&0xf])
+					if shift == 0 {
+						break
+					}
+				}
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// fmtString writes the string value `s`. Multi-line strings are written
+// as block strings when they can be represented as such, using the
+// current indentation
+func fmtString(ctx *fmtCtx, s string) {
+	buf := ctx.buf
+	if !isBlockPrintable(s) {
+		writeQuoted(buf, s)
+		return
+	}
+
+	buf.WriteString(`"""`)
+	for _, line := range strings.Split(strings.Replace(s, `"""`, `\"""`, -1), "\n") {
+		buf.WriteByte('\n')
+		if len(line) > 0 {
+			buf.Write(ctx.indent())
+			buf.WriteString(line)
+		}
+	}
+	buf.WriteByte('\n')
+	buf.Write(ctx.indent())
+	buf.WriteString(`"""`)
+}
+
+// isBlockPrintable returns true if `s` spans multiple lines, and
+// would be read back unchanged when written as a block string: the
+// common indentation and the blank lines at either end are removed
+// when block strings are read, and they can not contain control
+// characters other than tabs and new lines
+func isBlockPrintable(s string) bool {
+	if strings.IndexByte(s, '\n') < 0 {
+		return false
+	}
+
+	for _, r := range s {
+		if (r < 0x20 && r != '\t' && r != '\n') || r == 0x7f || r == utf8.RuneError {
+			return false
+		}
+	}
+
+	lines := strings.Split(s, "\n")
+	if isBlank(lines[0]) || isBlank(lines[len(lines)-1]) {
+		return false
+	}
+
+	for _, line := range lines {
+		if !isBlank(line) && line[0] != ' ' && line[0] != '\t' {
+			// there is no common indentation
+			return true
+		}
+	}
+	return false
+}
+
+func isBlank(s string) bool {
+	return strings.Trim(s, " \t") == ""
+}
@@ -0,0 +1,61 @@
+package format
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/lestrrat/go-graphql/model"
+)
+
+// Value returns the GraphQL notation for the literal value `v` on a
+// single line, e.g. `{ids: [1, 2], name: "R2-D2"}`. Unlike GraphQL,
+// strings are always quoted and never written as block strings
+func Value(v model.Value) string {
+	var buf bytes.Buffer
+	writeValue(&buf, v)
+	return buf.String()
+}
+
+func writeValue(buf *bytes.Buffer, v model.Value) {
+	switch v.Kind() {
+	case model.VariableKind:
+		buf.WriteByte('$')
+		buf.WriteString(v.Value().(string))
+	case model.IntKind:
+		buf.WriteString(strconv.Itoa(v.Value().(int)))
+	case model.FloatKind:
+		buf.WriteString(strconv.FormatFloat(v.Value().(float64), 'g', -1, 64))
+	case model.StringKind:
+		writeQuoted(buf, v.Value().(string))
+	case model.EnumKind:
+		buf.WriteString(v.Value().(string))
+	case model.BooleanKind:
+		buf.WriteString(strconv.FormatBool(v.Value().(bool)))
+	case model.ListKind:
+		buf.WriteByte('[')
+		i := 0
+		for elem := range v.(model.ListValue).Values() {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeValue(buf, elem)
+			i++
+		}
+		buf.WriteByte(']')
+	case model.ObjectKind:
+		buf.WriteByte('{')
+		i := 0
+		for f := range v.(model.ObjectValue).Fields() {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(f.Name())
+			buf.WriteString(": ")
+			writeValue(buf, f.Value())
+			i++
+		}
+		buf.WriteByte('}')
+	default:
+		buf.WriteString("null")
+	}
+}
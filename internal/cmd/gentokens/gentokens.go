@@ -37,11 +37,13 @@ func _main() error {
 		{Name: `BRACKET_R`, Description: `]`},
 		{Name: `BRACE_L`, Description: `{`},
 		{Name: `PIPE`, Description: `|`},
+		{Name: `AMP`, Description: `&`},
 		{Name: `BRACE_R`, Description: `}`},
 		{Name: `NAME`, Description: `Name`},
 		{Name: `INT`, Description: `Int`},
 		{Name: `FLOAT`, Description: `Float`},
 		{Name: `STRING`, Description: `String`},
+		{Name: `COMMENT`, Description: `Comment, only emitted when requested`},
 		{Name: `TokenTypeMax`, Description: `Max value for token types`},
 	}
 
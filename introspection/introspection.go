@@ -0,0 +1,161 @@
+// Package introspection defines the types and the meta fields of the
+// GraphQL introspection system, so that they can be shared between
+// the packages that need to know about them (e.g. execute, validate)
+package introspection
+
+import (
+	"sort"
+
+	"github.com/lestrrat/go-graphql/dsl"
+	"github.com/lestrrat/go-graphql/model"
+)
+
+// Values of the __TypeKind enum
+const (
+	KindScalar      = "SCALAR"
+	KindObject      = "OBJECT"
+	KindInterface   = "INTERFACE"
+	KindUnion       = "UNION"
+	KindEnum        = "ENUM"
+	KindInputObject = "INPUT_OBJECT"
+	KindList        = "LIST"
+	KindNonNull     = "NON_NULL"
+)
+
+var types = make(map[string]model.Definition)
+var names []string
+
+// SchemaField and TypeField are the definitions of the __schema and
+// __type meta fields, which are implicitly available on the query root
+// type. TypeNameField is the definition of the __typename meta field,
+// which is available on all object, interface and union types.
+//
+// These definitions do not have resolvers associated with them.
+var (
+	SchemaField   model.ObjectFieldDefinition
+	TypeField     model.ObjectFieldDefinition
+	TypeNameField model.ObjectFieldDefinition
+)
+
+func init() {
+	var typeRef = func(name string) model.Type {
+		return dsl.NotNull(dsl.NamedType(name))
+	}
+	var typeRefList = func(name string) model.Type {
+		return dsl.List(typeRef(name))
+	}
+	var includeDeprecated = func() model.ObjectFieldArgumentDefinition {
+		arg := dsl.ObjectFieldArgument(`includeDeprecated`, dsl.NamedType(`Boolean`))
+		v, _ := model.NewBoolValue(`false`)
+		arg.SetDefaultValue(v)
+		return arg
+	}
+
+	var defs = []model.Definition{
+		dsl.Object(`__Schema`,
+			dsl.ObjectField(`types`, dsl.NotNull(typeRefList(`__Type`))),
+			dsl.ObjectField(`queryType`, typeRef(`__Type`)),
+			dsl.ObjectField(`mutationType`, dsl.NamedType(`__Type`)),
+			dsl.ObjectField(`subscriptionType`, dsl.NamedType(`__Type`)),
+			dsl.ObjectField(`directives`, dsl.NotNull(typeRefList(`__Directive`))),
+		).Type(),
+		dsl.Object(`__Type`,
+			dsl.ObjectField(`kind`, typeRef(`__TypeKind`)),
+			dsl.ObjectField(`name`, dsl.NamedType(`String`)),
+			dsl.ObjectField(`description`, dsl.NamedType(`String`)),
+			dsl.ObjectField(`fields`, typeRefList(`__Field`), includeDeprecated()),
+			dsl.ObjectField(`interfaces`, typeRefList(`__Type`)),
+			dsl.ObjectField(`possibleTypes`, typeRefList(`__Type`)),
+			dsl.ObjectField(`enumValues`, typeRefList(`__EnumValue`), includeDeprecated()),
+			dsl.ObjectField(`inputFields`, typeRefList(`__InputValue`)),
+			dsl.ObjectField(`ofType`, dsl.NamedType(`__Type`)),
+		).Type(),
+		dsl.Object(`__Field`,
+			dsl.ObjectField(`name`, typeRef(`String`)),
+			dsl.ObjectField(`description`, dsl.NamedType(`String`)),
+			dsl.ObjectField(`args`, dsl.NotNull(typeRefList(`__InputValue`))),
+			dsl.ObjectField(`type`, typeRef(`__Type`)),
+			dsl.ObjectField(`isDeprecated`, typeRef(`Boolean`)),
+			dsl.ObjectField(`deprecationReason`, dsl.NamedType(`String`)),
+		).Type(),
+		dsl.Object(`__InputValue`,
+			dsl.ObjectField(`name`, typeRef(`String`)),
+			dsl.ObjectField(`description`, dsl.NamedType(`String`)),
+			dsl.ObjectField(`type`, typeRef(`__Type`)),
+			dsl.ObjectField(`defaultValue`, dsl.NamedType(`String`)),
+		).Type(),
+		dsl.Object(`__EnumValue`,
+			dsl.ObjectField(`name`, typeRef(`String`)),
+			dsl.ObjectField(`description`, dsl.NamedType(`String`)),
+			dsl.ObjectField(`isDeprecated`, typeRef(`Boolean`)),
+			dsl.ObjectField(`deprecationReason`, dsl.NamedType(`String`)),
+		).Type(),
+		dsl.Object(`__Directive`,
+			dsl.ObjectField(`name`, typeRef(`String`)),
+			dsl.ObjectField(`description`, dsl.NamedType(`String`)),
+			dsl.ObjectField(`locations`, dsl.NotNull(typeRefList(`__DirectiveLocation`))),
+			dsl.ObjectField(`args`, dsl.NotNull(typeRefList(`__InputValue`))),
+			dsl.ObjectField(`isRepeatable`, typeRef(`Boolean`)),
+		).Type(),
+		dsl.Enum(
+			dsl.Name(`__TypeKind`),
+			dsl.EnumValue(KindScalar, nil),
+			dsl.EnumValue(KindObject, nil),
+			dsl.EnumValue(KindInterface, nil),
+			dsl.EnumValue(KindUnion, nil),
+			dsl.EnumValue(KindEnum, nil),
+			dsl.EnumValue(KindInputObject, nil),
+			dsl.EnumValue(KindList, nil),
+			dsl.EnumValue(KindNonNull, nil),
+		),
+		dsl.Enum(
+			dsl.Name(`__DirectiveLocation`),
+			dsl.EnumValue(`QUERY`, nil),
+			dsl.EnumValue(`MUTATION`, nil),
+			dsl.EnumValue(`SUBSCRIPTION`, nil),
+			dsl.EnumValue(`FIELD`, nil),
+			dsl.EnumValue(`FRAGMENT_DEFINITION`, nil),
+			dsl.EnumValue(`FRAGMENT_SPREAD`, nil),
+			dsl.EnumValue(`INLINE_FRAGMENT`, nil),
+			dsl.EnumValue(`VARIABLE_DEFINITION`, nil),
+			dsl.EnumValue(`SCHEMA`, nil),
+			dsl.EnumValue(`SCALAR`, nil),
+			dsl.EnumValue(`OBJECT`, nil),
+			dsl.EnumValue(`FIELD_DEFINITION`, nil),
+			dsl.EnumValue(`ARGUMENT_DEFINITION`, nil),
+			dsl.EnumValue(`INTERFACE`, nil),
+			dsl.EnumValue(`UNION`, nil),
+			dsl.EnumValue(`ENUM`, nil),
+			dsl.EnumValue(`ENUM_VALUE`, nil),
+			dsl.EnumValue(`INPUT_OBJECT`, nil),
+			dsl.EnumValue(`INPUT_FIELD_DEFINITION`, nil),
+		),
+	}
+	for _, def := range defs {
+		types[def.Name()] = def
+		names = append(names, def.Name())
+	}
+	sort.Strings(names)
+
+	SchemaField = dsl.ObjectField(`__schema`, typeRef(`__Schema`))
+	TypeField = dsl.ObjectField(`__type`, dsl.NamedType(`__Type`),
+		dsl.ObjectFieldArgument(`name`, typeRef(`String`)),
+	)
+	TypeNameField = dsl.ObjectField(`__typename`, typeRef(`String`))
+}
+
+// LookupType returns the definition of the introspection type `name`
+func LookupType(name string) (model.Definition, bool) {
+	def, ok := types[name]
+	return def, ok
+}
+
+// Types returns the definitions of all introspection types, sorted
+// by their names
+func Types() []model.Definition {
+	list := make([]model.Definition, len(names))
+	for i, name := range names {
+		list[i] = types[name]
+	}
+	return list
+}
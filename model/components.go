@@ -78,3 +78,107 @@ type kindComponent Kind
 func (k kindComponent) Kind() Kind {
 	return Kind(k)
 }
+
+// fieldResolverComponent allows us to hide the field resolver and to
+// also provide a default Resolver() and SetResolver() for every
+// component that can be resolved during execution
+type fieldResolverComponent struct {
+	resolver FieldResolver
+}
+
+func (r fieldResolverComponent) Resolver() FieldResolver {
+	return r.resolver
+}
+
+func (r *fieldResolverComponent) SetResolver(v FieldResolver) {
+	r.resolver = v
+}
+
+// typeResolverComponent allows us to hide the type resolver and to
+// also provide a default TypeResolver() and SetTypeResolver() for
+// abstract types
+type typeResolverComponent struct {
+	resolver Resolver
+}
+
+func (r typeResolverComponent) TypeResolver() Resolver {
+	return r.resolver
+}
+
+func (r *typeResolverComponent) SetTypeResolver(v Resolver) {
+	r.resolver = v
+}
+
+// locationComponent provides the Location() and SetLocation() methods
+// for every node that can be created from the source text
+type locationComponent struct {
+	location Location
+}
+
+func (l locationComponent) Location() Location {
+	return l.location
+}
+
+func (l *locationComponent) SetLocation(loc Location) {
+	l.location = loc
+}
+
+// descriptionComponent provides the Description() and SetDescription()
+// methods for every type-system node that can be documented
+type descriptionComponent string
+
+func (d descriptionComponent) Description() string {
+	return string(d)
+}
+
+func (d *descriptionComponent) SetDescription(s string) {
+	*d = descriptionComponent(s)
+}
+
+// directivesComponent provides the Directives() and AddDirectives()
+// methods for every type-system node that can be annotated with
+// directives
+type directivesComponent struct {
+	directives DirectiveList
+}
+
+func (d directivesComponent) Directives() chan Directive {
+	return d.directives.Iterator()
+}
+
+func (d *directivesComponent) AddDirectives(list ...Directive) {
+	d.directives.Add(list...)
+}
+
+// extensionComponent marks the definitions that extend an existing
+// type or schema
+type extensionComponent struct{}
+
+func (extensionComponent) IsExtension() bool {
+	return true
+}
+
+// interfacesComponent provides the methods of Implementer for object
+// types and interfaces
+type interfacesComponent struct {
+	interfaces NamedTypeList
+}
+
+func (c interfacesComponent) Interfaces() chan NamedType {
+	return c.interfaces.Iterator()
+}
+
+func (c *interfacesComponent) AddInterfaces(list ...NamedType) {
+	c.interfaces.Add(list...)
+}
+
+// Implements returns true if the interface `name` is among the
+// interfaces that are implemented
+func (c interfacesComponent) Implements(name string) bool {
+	for _, t := range c.interfaces {
+		if t.Name() == name {
+			return true
+		}
+	}
+	return false
+}
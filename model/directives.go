@@ -17,3 +17,159 @@ func (d directive) Arguments() chan Argument {
 func (d *directive) AddArguments(args ...Argument) {
 	d.arguments.Add(args...)
 }
+
+// DirectiveLocation is a location where a directive may be used
+type DirectiveLocation string
+
+// Locations in executable documents
+const (
+	DirectiveLocationQuery              DirectiveLocation = "QUERY"
+	DirectiveLocationMutation           DirectiveLocation = "MUTATION"
+	DirectiveLocationSubscription       DirectiveLocation = "SUBSCRIPTION"
+	DirectiveLocationField              DirectiveLocation = "FIELD"
+	DirectiveLocationFragmentDefinition DirectiveLocation = "FRAGMENT_DEFINITION"
+	DirectiveLocationFragmentSpread     DirectiveLocation = "FRAGMENT_SPREAD"
+	DirectiveLocationInlineFragment     DirectiveLocation = "INLINE_FRAGMENT"
+	DirectiveLocationVariableDefinition DirectiveLocation = "VARIABLE_DEFINITION"
+)
+
+// Locations in type system documents
+const (
+	DirectiveLocationSchema               DirectiveLocation = "SCHEMA"
+	DirectiveLocationScalar               DirectiveLocation = "SCALAR"
+	DirectiveLocationObject               DirectiveLocation = "OBJECT"
+	DirectiveLocationFieldDefinition      DirectiveLocation = "FIELD_DEFINITION"
+	DirectiveLocationArgumentDefinition   DirectiveLocation = "ARGUMENT_DEFINITION"
+	DirectiveLocationInterface            DirectiveLocation = "INTERFACE"
+	DirectiveLocationUnion                DirectiveLocation = "UNION"
+	DirectiveLocationEnum                 DirectiveLocation = "ENUM"
+	DirectiveLocationEnumValue            DirectiveLocation = "ENUM_VALUE"
+	DirectiveLocationInputObject          DirectiveLocation = "INPUT_OBJECT"
+	DirectiveLocationInputFieldDefinition DirectiveLocation = "INPUT_FIELD_DEFINITION"
+)
+
+// IsValid returns true if `l` is one of the locations defined by the
+// GraphQL specification
+func (l DirectiveLocation) IsValid() bool {
+	switch l {
+	case DirectiveLocationQuery, DirectiveLocationMutation, DirectiveLocationSubscription,
+		DirectiveLocationField, DirectiveLocationFragmentDefinition, DirectiveLocationFragmentSpread,
+		DirectiveLocationInlineFragment, DirectiveLocationVariableDefinition,
+		DirectiveLocationSchema, DirectiveLocationScalar, DirectiveLocationObject,
+		DirectiveLocationFieldDefinition, DirectiveLocationArgumentDefinition, DirectiveLocationInterface,
+		DirectiveLocationUnion, DirectiveLocationEnum, DirectiveLocationEnumValue,
+		DirectiveLocationInputObject, DirectiveLocationInputFieldDefinition:
+		return true
+	}
+	return false
+}
+
+func NewDirectiveDefinition(name string) DirectiveDefinition {
+	return &directiveDefinition{
+		nameComponent: nameComponent(name),
+	}
+}
+
+func (def *directiveDefinition) Arguments() chan ObjectFieldArgumentDefinition {
+	return def.arguments.Iterator()
+}
+
+func (def *directiveDefinition) AddArguments(list ...ObjectFieldArgumentDefinition) {
+	def.arguments.Add(list...)
+}
+
+func (def *directiveDefinition) Locations() []DirectiveLocation {
+	list := make([]DirectiveLocation, len(def.locations))
+	copy(list, def.locations)
+	return list
+}
+
+func (def *directiveDefinition) AddLocations(list ...DirectiveLocation) {
+	def.locations = append(def.locations, list...)
+}
+
+func (def *directiveDefinition) IsRepeatable() bool {
+	return def.repeatable
+}
+
+func (def *directiveDefinition) SetRepeatable(b bool) {
+	def.repeatable = b
+}
+
+var builtinDirectives []DirectiveDefinition
+
+func init() {
+	ifArg := func(description string) ObjectFieldArgumentDefinition {
+		typ := NewNamedType("Boolean")
+		typ.SetNullable(false)
+		arg := NewObjectFieldArgumentDefinition("if", typ)
+		arg.SetDescription(description)
+		return arg
+	}
+
+	include := NewDirectiveDefinition("include")
+	include.SetDescription("Directs the executor to include this field or fragment only when the `if` argument is true.")
+	include.AddArguments(ifArg("Included when true."))
+	include.AddLocations(DirectiveLocationField, DirectiveLocationFragmentSpread, DirectiveLocationInlineFragment)
+
+	skip := NewDirectiveDefinition("skip")
+	skip.SetDescription("Directs the executor to skip this field or fragment when the `if` argument is true.")
+	skip.AddArguments(ifArg("Skipped when true."))
+	skip.AddLocations(DirectiveLocationField, DirectiveLocationFragmentSpread, DirectiveLocationInlineFragment)
+
+	reason := NewObjectFieldArgumentDefinition("reason", NewNamedType("String"))
+	reason.SetDescription("Explains why this element was deprecated.")
+	reason.SetDefaultValue(NewStringValue("No longer supported"))
+	deprecated := NewDirectiveDefinition("deprecated")
+	deprecated.SetDescription("Marks an element of a GraphQL schema as no longer supported.")
+	deprecated.AddArguments(reason)
+	deprecated.AddLocations(DirectiveLocationFieldDefinition, DirectiveLocationArgumentDefinition, DirectiveLocationInputFieldDefinition, DirectiveLocationEnumValue)
+
+	urlType := NewNamedType("String")
+	urlType.SetNullable(false)
+	url := NewObjectFieldArgumentDefinition("url", urlType)
+	url.SetDescription("The URL that specifies the behavior of this scalar.")
+	specifiedBy := NewDirectiveDefinition("specifiedBy")
+	specifiedBy.SetDescription("Exposes a URL that specifies the behavior of this scalar.")
+	specifiedBy.AddArguments(url)
+	specifiedBy.AddLocations(DirectiveLocationScalar)
+
+	builtinDirectives = []DirectiveDefinition{include, skip, deprecated, specifiedBy}
+}
+
+// BuiltinDirectives returns the definitions of the directives that are
+// provided by GraphQL itself (@include, @skip, @deprecated and
+// @specifiedBy). The definitions are copies, so modifying them does
+// not change the built-in directives
+func BuiltinDirectives() []DirectiveDefinition {
+	list := make([]DirectiveDefinition, len(builtinDirectives))
+	for i, def := range builtinDirectives {
+		list[i] = def.(*directiveDefinition).clone()
+	}
+	return list
+}
+
+// LookupBuiltinDirective returns a copy of the definition of the
+// built-in directive `name` (without the leading @), if there is one
+func LookupBuiltinDirective(name string) (DirectiveDefinition, bool) {
+	for _, def := range builtinDirectives {
+		if def.Name() == name {
+			return def.(*directiveDefinition).clone(), true
+		}
+	}
+	return nil, false
+}
+
+// clone copies the definition along with its arguments, so that
+// neither can be modified through the copy
+func (def *directiveDefinition) clone() *directiveDefinition {
+	c := *def
+	c.arguments = make(ObjectFieldArgumentDefinitionList, len(def.arguments))
+	for i, arg := range def.arguments {
+		a := *arg.(*objectFieldArgumentDefinition)
+		a.directives = append(DirectiveList(nil), a.directives...)
+		c.arguments[i] = &a
+	}
+	c.locations = append([]DirectiveLocation(nil), def.locations...)
+	return &c
+}
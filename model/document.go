@@ -15,6 +15,82 @@ func (doc *document) LookupQuery(name string) (OperationDefinition, bool) {
 	return def, ok
 }
 
+func (doc *document) LookupMutation(name string) (OperationDefinition, bool) {
+	doc.mmu.Lock()
+	defer doc.mmu.Unlock()
+
+	if doc.mutations == nil {
+		return nil, false
+	}
+	def, ok := doc.mutations[name]
+	return def, ok
+}
+
+func (doc *document) LookupSubscription(name string) (OperationDefinition, bool) {
+	doc.smu.Lock()
+	defer doc.smu.Unlock()
+
+	if doc.subscriptions == nil {
+		return nil, false
+	}
+	def, ok := doc.subscriptions[name]
+	return def, ok
+}
+
+func (doc *document) LookupFragment(name string) (FragmentDefinition, bool) {
+	doc.fmu.Lock()
+	defer doc.fmu.Unlock()
+
+	if doc.fragments == nil {
+		return nil, false
+	}
+	def, ok := doc.fragments[name]
+	return def, ok
+}
+
+// LookupType returns the type system definition (object, interface,
+// enum, union, input, or scalar) registered under the given name
+func (doc *document) LookupType(name string) (Definition, bool) {
+	doc.tmu.Lock()
+	defer doc.tmu.Unlock()
+
+	if doc.types == nil {
+		return nil, false
+	}
+	def, ok := doc.types[name]
+	return def, ok
+}
+
+// LookupDirective returns the directive definition named `name`
+// (without the leading @), if the document defines one
+func (doc *document) LookupDirective(name string) (DirectiveDefinition, bool) {
+	doc.dmu.Lock()
+	defer doc.dmu.Unlock()
+
+	if doc.directives == nil {
+		return nil, false
+	}
+	def, ok := doc.directives[name]
+	return def, ok
+}
+
+// LookupSchema returns the schema definition, if the document has one
+func (doc *document) LookupSchema() (Schema, bool) {
+	doc.tmu.Lock()
+	defer doc.tmu.Unlock()
+
+	return doc.schema, doc.schema != nil
+}
+
+func (doc *document) addType(def Definition) {
+	doc.tmu.Lock()
+	defer doc.tmu.Unlock()
+	if doc.types == nil {
+		doc.types = make(map[string]Definition)
+	}
+	doc.types[def.Name()] = def
+}
+
 func (doc *document) addDefinition(def Definition) {
 	switch def.(type) {
 	case OperationDefinition:
@@ -35,8 +111,35 @@ func (doc *document) addDefinition(def Definition) {
 				doc.mutations = make(map[string]OperationDefinition)
 			}
 			m = doc.mutations
+		case OperationTypeSubscription:
+			doc.smu.Lock()
+			defer doc.smu.Unlock()
+			if doc.subscriptions == nil {
+				doc.subscriptions = make(map[string]OperationDefinition)
+			}
+			m = doc.subscriptions
 		}
 		m[odef.Name()] = odef
+	case FragmentDefinition:
+		doc.fmu.Lock()
+		defer doc.fmu.Unlock()
+		if doc.fragments == nil {
+			doc.fragments = make(map[string]FragmentDefinition)
+		}
+		doc.fragments[def.Name()] = def.(FragmentDefinition)
+	case Schema:
+		doc.tmu.Lock()
+		defer doc.tmu.Unlock()
+		doc.schema = def.(Schema)
+	case DirectiveDefinition:
+		doc.dmu.Lock()
+		defer doc.dmu.Unlock()
+		if doc.directives == nil {
+			doc.directives = make(map[string]DirectiveDefinition)
+		}
+		doc.directives[def.Name()] = def.(DirectiveDefinition)
+	case ObjectDefinition, InterfaceDefinition, EnumDefinition, UnionDefinition, InputDefinition, ScalarDefinition:
+		doc.addType(def)
 	}
 }
 
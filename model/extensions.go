@@ -0,0 +1,456 @@
+package model
+
+import (
+	"github.com/pkg/errors"
+)
+
+func NewObjectExtension(name string) ObjectExtension {
+	return &objectExtension{
+		nameComponent: nameComponent(name),
+	}
+}
+
+func (def objectExtension) Fields() chan ObjectFieldDefinition {
+	return def.fields.Iterator()
+}
+
+func (def *objectExtension) AddFields(list ...ObjectFieldDefinition) {
+	def.fields.Add(list...)
+}
+
+func NewInterfaceExtension(name string) InterfaceExtension {
+	return &interfaceExtension{
+		nameComponent: nameComponent(name),
+	}
+}
+
+func (def interfaceExtension) Fields() chan InterfaceFieldDefinition {
+	return def.fields.Iterator()
+}
+
+func (def *interfaceExtension) AddFields(list ...InterfaceFieldDefinition) {
+	def.fields.Add(list...)
+}
+
+func NewUnionExtension(name string) UnionExtension {
+	return &unionExtension{
+		nameComponent: nameComponent(name),
+	}
+}
+
+func (def unionExtension) Types() chan Type {
+	return def.types.Iterator()
+}
+
+func (def *unionExtension) AddTypes(list ...Type) {
+	def.types.Add(list...)
+}
+
+func NewEnumExtension(name string) EnumExtension {
+	return &enumExtension{
+		nameComponent: nameComponent(name),
+	}
+}
+
+func (def enumExtension) Elements() chan EnumElementDefinition {
+	return def.elements.Iterator()
+}
+
+func (def *enumExtension) AddElements(list ...EnumElementDefinition) {
+	def.elements.Add(list...)
+}
+
+func NewInputExtension(name string) InputExtension {
+	return &inputExtension{
+		nameComponent: nameComponent(name),
+	}
+}
+
+func (def inputExtension) Fields() chan InputFieldDefinition {
+	return def.fields.Iterator()
+}
+
+func (def *inputExtension) AddFields(list ...InputFieldDefinition) {
+	def.fields.Add(list...)
+}
+
+func NewScalarExtension(name string) ScalarExtension {
+	return &scalarExtension{
+		nameComponent: nameComponent(name),
+	}
+}
+
+func NewSchemaExtension() SchemaExtension {
+	return &schemaExtension{}
+}
+
+func (s schemaExtension) Name() string {
+	return ""
+}
+
+func (s schemaExtension) Query() NamedType {
+	return s.query
+}
+
+func (s *schemaExtension) SetQuery(q NamedType) {
+	s.query = q
+}
+
+func (s schemaExtension) Mutation() NamedType {
+	return s.mutation
+}
+
+func (s *schemaExtension) SetMutation(q NamedType) {
+	s.mutation = q
+}
+
+func (s schemaExtension) Subscription() NamedType {
+	return s.subscription
+}
+
+func (s *schemaExtension) SetSubscription(q NamedType) {
+	s.subscription = q
+}
+
+// MergeExtensions creates a new document from the definitions found
+// in `docs`, where the type and schema extensions have been merged
+// into the definitions that they extend. Extensions may appear before
+// or after the definitions that they extend, and in a different
+// document. The definitions that are extended are copied, so neither
+// `docs` nor the extensions in them are modified.
+//
+// An error is returned if an extension refers to a type that is not
+// defined, to a type of a different kind, or if it redefines fields,
+// values, member types or root operation types that already exist.
+func MergeExtensions(docs ...Document) (Document, error) {
+	var defs []Definition
+	var extensions []Extension
+	extended := make(map[string]struct{})
+	var extendsSchema bool
+	for _, doc := range docs {
+		for def := range doc.Definitions() {
+			if ext, ok := def.(Extension); ok {
+				extensions = append(extensions, ext)
+				if _, ok := ext.(SchemaExtension); ok {
+					extendsSchema = true
+				} else {
+					extended[ext.Name()] = struct{}{}
+				}
+				continue
+			}
+			defs = append(defs, def)
+		}
+	}
+
+	var list DefinitionList
+	for _, def := range defs {
+		if _, ok := def.(Schema); ok {
+			if extendsSchema {
+				def = cloneDefinition(def)
+			}
+		} else if _, ok := extended[def.Name()]; ok {
+			def = cloneDefinition(def)
+		}
+		list.Add(def)
+	}
+
+	merged := NewDocument()
+	merged.AddDefinitions(list...)
+
+	for _, ext := range extensions {
+		if err := mergeExtension(merged, ext); err != nil {
+			loc := ext.Location()
+			if loc.Start.Line > 0 {
+				return nil, errors.Wrapf(err, `failed to merge extension at line %d, column %d`, loc.Start.Line, loc.Start.Column)
+			}
+			return nil, errors.Wrap(err, `failed to merge extension`)
+		}
+	}
+	return merged, nil
+}
+
+// cloneDefinition returns a copy of `def` that extensions can be
+// merged into without modifying `def`. The lists that extensions add
+// to are copied, while their elements are shared. Definitions that
+// can not be extended are returned as is
+func cloneDefinition(def Definition) Definition {
+	switch def.(type) {
+	case *objectDefinition:
+		c := *def.(*objectDefinition)
+		c.directives = append(DirectiveList(nil), c.directives...)
+		c.interfaces = append(NamedTypeList(nil), c.interfaces...)
+		c.fields = append(ObjectFieldDefinitionList(nil), c.fields...)
+		return &c
+	case *interfaceDefinition:
+		c := *def.(*interfaceDefinition)
+		c.directives = append(DirectiveList(nil), c.directives...)
+		c.interfaces = append(NamedTypeList(nil), c.interfaces...)
+		c.fields = append(InterfaceFieldDefinitionList(nil), c.fields...)
+		return &c
+	case *unionDefinition:
+		c := *def.(*unionDefinition)
+		c.directives = append(DirectiveList(nil), c.directives...)
+		c.types = append(TypeList(nil), c.types...)
+		return &c
+	case *enumDefinition:
+		c := *def.(*enumDefinition)
+		c.directives = append(DirectiveList(nil), c.directives...)
+		c.elements = append(EnumElementDefinitionList(nil), c.elements...)
+		return &c
+	case *inputDefinition:
+		c := *def.(*inputDefinition)
+		c.directives = append(DirectiveList(nil), c.directives...)
+		c.fields = append(InputFieldDefinitionList(nil), c.fields...)
+		return &c
+	case *scalarDefinition:
+		c := *def.(*scalarDefinition)
+		c.directives = append(DirectiveList(nil), c.directives...)
+		return &c
+	case *schema:
+		c := *def.(*schema)
+		c.directives = append(DirectiveList(nil), c.directives...)
+		c.types = append(NamedTypeList(nil), c.types...)
+		return &c
+	}
+	return def
+}
+
+func mergeExtension(doc Document, ext Extension) error {
+	if sext, ok := ext.(SchemaExtension); ok {
+		return mergeSchemaExtension(doc, sext)
+	}
+
+	def, ok := doc.LookupType(ext.Name())
+	if !ok {
+		return errors.Errorf(`cannot extend unknown type %s`, ext.Name())
+	}
+
+	switch ext.(type) {
+	case ObjectExtension:
+		base, ok := def.(ObjectDefinition)
+		if !ok {
+			return errors.Errorf(`cannot extend %s as an object type`, ext.Name())
+		}
+		return mergeObjectExtension(base, ext.(ObjectExtension))
+	case InterfaceExtension:
+		base, ok := def.(InterfaceDefinition)
+		if !ok {
+			return errors.Errorf(`cannot extend %s as an interface`, ext.Name())
+		}
+		return mergeInterfaceExtension(base, ext.(InterfaceExtension))
+	case UnionExtension:
+		base, ok := def.(UnionDefinition)
+		if !ok {
+			return errors.Errorf(`cannot extend %s as a union`, ext.Name())
+		}
+		return mergeUnionExtension(base, ext.(UnionExtension))
+	case EnumExtension:
+		base, ok := def.(EnumDefinition)
+		if !ok {
+			return errors.Errorf(`cannot extend %s as an enum`, ext.Name())
+		}
+		return mergeEnumExtension(base, ext.(EnumExtension))
+	case InputExtension:
+		base, ok := def.(InputDefinition)
+		if !ok {
+			return errors.Errorf(`cannot extend %s as an input type`, ext.Name())
+		}
+		return mergeInputExtension(base, ext.(InputExtension))
+	case ScalarExtension:
+		base, ok := def.(ScalarDefinition)
+		if !ok {
+			return errors.Errorf(`cannot extend %s as a scalar`, ext.Name())
+		}
+		base.AddDirectives(directiveSlice(ext.Directives())...)
+		return nil
+	}
+	return errors.Errorf(`unsupported extension %T`, ext)
+}
+
+func mergeObjectExtension(base ObjectDefinition, ext ObjectExtension) error {
+	interfaces, err := mergeInterfaces(base.Name(), base, ext.Interfaces())
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]struct{})
+	for f := range base.Fields() {
+		existing[f.Name()] = struct{}{}
+	}
+
+	var fields ObjectFieldDefinitionList
+	for f := range ext.Fields() {
+		if _, ok := existing[f.Name()]; ok {
+			return errors.Errorf(`field %s.%s is already defined`, base.Name(), f.Name())
+		}
+		existing[f.Name()] = struct{}{}
+		fields.Add(f)
+	}
+
+	base.AddDirectives(directiveSlice(ext.Directives())...)
+	base.AddInterfaces(interfaces...)
+	base.AddFields(fields...)
+	return nil
+}
+
+func mergeInterfaceExtension(base InterfaceDefinition, ext InterfaceExtension) error {
+	interfaces, err := mergeInterfaces(base.Name(), base, ext.Interfaces())
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]struct{})
+	for f := range base.Fields() {
+		existing[f.Name()] = struct{}{}
+	}
+
+	var fields InterfaceFieldDefinitionList
+	for f := range ext.Fields() {
+		if _, ok := existing[f.Name()]; ok {
+			return errors.Errorf(`field %s.%s is already defined`, base.Name(), f.Name())
+		}
+		existing[f.Name()] = struct{}{}
+		fields.Add(f)
+	}
+
+	base.AddDirectives(directiveSlice(ext.Directives())...)
+	base.AddInterfaces(interfaces...)
+	base.AddFields(fields...)
+	return nil
+}
+
+func mergeUnionExtension(base UnionDefinition, ext UnionExtension) error {
+	existing := make(map[string]struct{})
+	for t := range base.Types() {
+		if n, ok := t.(Namer); ok {
+			existing[n.Name()] = struct{}{}
+		}
+	}
+
+	var types TypeList
+	for t := range ext.Types() {
+		if n, ok := t.(Namer); ok {
+			if _, ok := existing[n.Name()]; ok {
+				return errors.Errorf(`union %s already includes %s`, base.Name(), n.Name())
+			}
+			existing[n.Name()] = struct{}{}
+		}
+		types.Add(t)
+	}
+
+	base.AddDirectives(directiveSlice(ext.Directives())...)
+	base.AddTypes(types...)
+	return nil
+}
+
+func mergeEnumExtension(base EnumDefinition, ext EnumExtension) error {
+	// the parser numbers the values of each enum definition (and
+	// extension) from 1, so the values that are added are renumbered
+	// to follow the ones already defined
+	existing := make(map[string]struct{})
+	var maxval int
+	for e := range base.Elements() {
+		existing[e.Name()] = struct{}{}
+		if v := e.Value(); v != nil && v.Kind() == IntKind && v.Value().(int) > maxval {
+			maxval = v.Value().(int)
+		}
+	}
+
+	var elements EnumElementDefinitionList
+	for e := range ext.Elements() {
+		if _, ok := existing[e.Name()]; ok {
+			return errors.Errorf(`enum value %s.%s is already defined`, base.Name(), e.Name())
+		}
+		existing[e.Name()] = struct{}{}
+
+		// the element is copied, so that the extension is not modified
+		if elem, ok := e.(*enumElementDefinition); ok {
+			if v := elem.Value(); v != nil && v.Kind() == IntKind {
+				c := *elem
+				maxval++
+				c.SetValue(NewIntValue(maxval))
+				e = &c
+			}
+		}
+		elements.Add(e)
+	}
+
+	base.AddDirectives(directiveSlice(ext.Directives())...)
+	base.AddElements(elements...)
+	return nil
+}
+
+func mergeInputExtension(base InputDefinition, ext InputExtension) error {
+	existing := make(map[string]struct{})
+	for f := range base.Fields() {
+		existing[f.Name()] = struct{}{}
+	}
+
+	var fields InputFieldDefinitionList
+	for f := range ext.Fields() {
+		if _, ok := existing[f.Name()]; ok {
+			return errors.Errorf(`field %s.%s is already defined`, base.Name(), f.Name())
+		}
+		existing[f.Name()] = struct{}{}
+		fields.Add(f)
+	}
+
+	base.AddDirectives(directiveSlice(ext.Directives())...)
+	base.AddFields(fields...)
+	return nil
+}
+
+func mergeSchemaExtension(doc Document, ext SchemaExtension) error {
+	s, ok := doc.LookupSchema()
+	if !ok {
+		return errors.New(`cannot extend schema: no schema definition`)
+	}
+
+	for _, root := range []struct {
+		key string
+		get func() NamedType
+		set func(NamedType)
+		typ NamedType
+	}{
+		{"query", s.Query, s.SetQuery, ext.Query()},
+		{"mutation", s.Mutation, s.SetMutation, ext.Mutation()},
+		{"subscription", s.Subscription, s.SetSubscription, ext.Subscription()},
+	} {
+		if root.typ == nil {
+			continue
+		}
+		if root.get() != nil {
+			return errors.Errorf(`schema already defines the %s operation type`, root.key)
+		}
+		root.set(root.typ)
+	}
+
+	s.AddDirectives(directiveSlice(ext.Directives())...)
+	return nil
+}
+
+// mergeInterfaces returns the interfaces in `ch` that may be added to
+// those implemented by `base`
+func mergeInterfaces(name string, base Implementer, ch chan NamedType) (NamedTypeList, error) {
+	var interfaces NamedTypeList
+	for t := range ch {
+		if base.Implements(t.Name()) {
+			return nil, errors.Errorf(`type %s already implements %s`, name, t.Name())
+		}
+		for _, added := range interfaces {
+			if added.Name() == t.Name() {
+				return nil, errors.Errorf(`type %s already implements %s`, name, t.Name())
+			}
+		}
+		interfaces.Add(t)
+	}
+	return interfaces, nil
+}
+
+func directiveSlice(ch chan Directive) []Directive {
+	var list []Directive
+	for d := range ch {
+		list = append(list, d)
+	}
+	return list
+}
@@ -1,6 +1,10 @@
 package model
 
-import "sync"
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+)
 
 // Namer represents all those that have a name to share
 type Namer interface {
@@ -28,6 +32,37 @@ type Nullable interface {
 	SetNullable(bool)
 }
 
+// Position describes a position in the source text
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number in runes, starting at 1
+}
+
+// Location describes the span of source text that a node was parsed
+// from. The End position points right after the last character of
+// the node. Nodes that were not created by the parser have a zero
+// Location
+type Location struct {
+	Source string // name of the source (e.g. a file name), may be empty
+	Start  Position
+	End    Position
+}
+
+// Describer represents the type-system nodes that can carry a
+// description. An empty string means that there is no description
+type Describer interface {
+	Description() string
+	SetDescription(string)
+}
+
+// Locator represents all those nodes that know where they appear
+// in the source text
+type Locator interface {
+	Location() Location
+	SetLocation(Location)
+}
+
 type DirectivesContainer interface {
 	Directives() chan Directive
 	AddDirectives(...Directive)
@@ -44,28 +79,80 @@ type Definition interface {
 	Namer
 }
 
+// Resolver is used to determine the concrete object type of a value
+// whose declared type is abstract (interfaces and unions)
+type Resolver interface {
+	Resolve(interface{}) Type
+}
+
+// TypeResolverContainer represents abstract types which may have a
+// Resolver associated with them
+type TypeResolverContainer interface {
+	TypeResolver() Resolver
+	SetTypeResolver(Resolver)
+}
+
+// FieldResolver is responsible for producing the value of an object
+// field during execution. It receives the parent value and the
+// coerced arguments for the field.
+type FieldResolver interface {
+	ResolveField(context.Context, interface{}, map[string]interface{}) (interface{}, error)
+}
+
+// Implementer represents the object types and interfaces that may
+// implement interfaces
+type Implementer interface {
+	Interfaces() chan NamedType
+	AddInterfaces(...NamedType)
+	Implements(string) bool
+}
+
+// FieldResolverContainer represents those that may have a FieldResolver
+// associated with them
+type FieldResolverContainer interface {
+	Resolver() FieldResolver
+	SetResolver(FieldResolver)
+}
+
 type Document interface {
 	Definitions() chan Definition
 	AddDefinitions(...Definition)
+	LookupQuery(string) (OperationDefinition, bool)
+	LookupMutation(string) (OperationDefinition, bool)
+	LookupSubscription(string) (OperationDefinition, bool)
+	LookupFragment(string) (FragmentDefinition, bool)
+	LookupType(string) (Definition, bool)
+	LookupDirective(string) (DirectiveDefinition, bool)
+	LookupSchema() (Schema, bool)
 }
 type document struct {
 	definitions DefinitionList
-	types       TypeList
-
-	qmu       sync.Mutex // lock queries
-	mmu       sync.Mutex // lock mutations
-	queries   map[string]OperationDefinition
-	mutations map[string]OperationDefinition
+	schema      Schema
+
+	qmu           sync.Mutex // lock queries
+	mmu           sync.Mutex // lock mutations
+	smu           sync.Mutex // lock subscriptions
+	fmu           sync.Mutex // lock fragments
+	tmu           sync.Mutex // lock types and schema
+	dmu           sync.Mutex // lock directives
+	queries       map[string]OperationDefinition
+	mutations     map[string]OperationDefinition
+	subscriptions map[string]OperationDefinition
+	fragments     map[string]FragmentDefinition
+	types         map[string]Definition
+	directives    map[string]DirectiveDefinition
 }
 
 type OperationType string
 
 const (
-	OperationTypeQuery    OperationType = "query"
-	OperationTypeMutation OperationType = "mutation"
+	OperationTypeQuery        OperationType = "query"
+	OperationTypeMutation     OperationType = "mutation"
+	OperationTypeSubscription OperationType = "subscription"
 )
 
 type OperationDefinition interface {
+	Locator
 	Namer
 	DirectivesContainer
 	SelectionsContainer
@@ -78,6 +165,7 @@ type OperationDefinition interface {
 }
 
 type operationDefinition struct {
+	locationComponent
 	typ        OperationType
 	hasName    bool
 	name       string
@@ -87,6 +175,7 @@ type operationDefinition struct {
 }
 
 type FragmentDefinition interface {
+	Locator
 	Namer
 	Typer
 	DirectivesContainer
@@ -94,6 +183,7 @@ type FragmentDefinition interface {
 }
 
 type fragmentDefinition struct {
+	locationComponent
 	nameComponent
 	typeComponent
 	directives DirectiveList
@@ -101,12 +191,16 @@ type fragmentDefinition struct {
 }
 
 type VariableDefinition interface {
+	Locator
+	DirectivesContainer
 	Namer
 	Typer
 	DefaultValuer
 }
 
 type variableDefinition struct {
+	locationComponent
+	directivesComponent
 	nameComponent
 	typeComponent
 	defaultValueComponent
@@ -114,137 +208,240 @@ type variableDefinition struct {
 
 // ObjectDefinition is a definition of a new object type
 type ObjectDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Type
 	Nullable
 	AddFields(...ObjectFieldDefinition)
 	Fields() chan ObjectFieldDefinition
-	HasImplements() bool
-	Implements() NamedType
-	SetImplements(NamedType)
+	Implementer
 }
 
 type objectDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nullable
 	nameComponent
-	fields        ObjectFieldDefinitionList
-	hasImplements bool
-	implements    NamedType
+	interfacesComponent
+	fields ObjectFieldDefinitionList
 }
 
 type ObjectFieldArgumentDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Typer
 	DefaultValuer
 }
 
 type objectFieldArgumentDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nameComponent
 	typeComponent
 	defaultValueComponent
 }
 
 type ObjectFieldDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Typer
+	FieldResolverContainer
 	Arguments() chan ObjectFieldArgumentDefinition
 	AddArguments(...ObjectFieldArgumentDefinition)
 }
 
 type objectFieldDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nameComponent
 	typeComponent
+	fieldResolverComponent
 	arguments ObjectFieldArgumentDefinitionList
 }
 
 type EnumDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Elements() chan EnumElementDefinition
 	AddElements(...EnumElementDefinition)
 }
 
 type enumDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nullable // is this kosher?
 	nameComponent
 	elements EnumElementDefinitionList
 }
 
 type EnumElementDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Value() Value
 }
 
 type enumElementDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nameComponent
 	valueComponent
 }
 
 type InterfaceDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Nullable
 	Namer
+	Implementer
+	TypeResolverContainer
 	Fields() chan InterfaceFieldDefinition
 	AddFields(...InterfaceFieldDefinition)
 }
 
 type interfaceDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nullable
 	nameComponent
+	typeResolverComponent
+	interfacesComponent
 	fields InterfaceFieldDefinitionList
 }
 
 type InterfaceFieldDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Typer
+	Arguments() chan ObjectFieldArgumentDefinition
+	AddArguments(...ObjectFieldArgumentDefinition)
 }
 
 type interfaceFieldDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nameComponent
 	typeComponent
+	arguments ObjectFieldArgumentDefinitionList
+}
+
+// ScalarDefinition is a definition of a scalar type. Scalars convert
+// values between their external representation and the one used by
+// resolvers through Serialize, ParseValue and ParseLiteral. Scalars
+// without custom functions pass values through as is
+type ScalarDefinition interface {
+	Locator
+	Describer
+	Namer
+	Type
+	Nullable
+	DirectivesContainer
+
+	// Serialize converts a value produced by a resolver into the
+	// representation used in the result
+	Serialize(interface{}) (interface{}, error)
+	// ParseValue converts a value provided as a variable
+	ParseValue(interface{}) (interface{}, error)
+	// ParseLiteral converts a literal value found in a document
+	ParseLiteral(Value) (interface{}, error)
+
+	SetSerializeFunc(SerializeFunc)
+	SetParseValueFunc(ParseValueFunc)
+	SetParseLiteralFunc(ParseLiteralFunc)
+}
+
+type scalarDefinition struct {
+	locationComponent
+	descriptionComponent
+	nullable
+	nameComponent
+	directivesComponent
+	serialize    SerializeFunc
+	parseValue   ParseValueFunc
+	parseLiteral ParseLiteralFunc
 }
 
 type InputDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Fields() chan InputFieldDefinition
 	AddFields(...InputFieldDefinition)
 }
 
 type inputDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nameComponent
 	fields InputFieldDefinitionList
 }
 
 type InputFieldDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
 	Typer
+	DefaultValuer
 }
 
 type inputFieldDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nameComponent
 	typeComponent
+	defaultValueComponent
 }
 
 type NamedType interface {
+	Locator
 	Nullable
 	Namer
 }
 
 type namedType struct {
+	locationComponent
 	kindComponent
 	nullable
 	nameComponent
 }
 
 type ListType interface {
+	Locator
 	Nullable
 	Type() Type
 }
 
 type listType struct {
+	locationComponent
 	nullable
 	typeComponent
 }
 
 type Value interface {
+	Locator
 	Kind() Kind
 	Value() interface{}
 }
@@ -255,39 +452,49 @@ type Variable interface {
 }
 
 type variable struct {
+	locationComponent
 	nameComponent
 }
 
 type intValue struct {
+	locationComponent
 	value int
 }
 
 type floatValue struct {
+	locationComponent
 	value float64
 }
 
 type stringValue struct {
+	locationComponent
 	value string
 }
 
 type boolValue struct {
+	locationComponent
 	value bool
 }
 
-type nullValue struct{}
+type nullValue struct {
+	locationComponent
+}
 
 type enumValue struct {
+	locationComponent
 	nameComponent
 }
 
 // ObjectField represents a literal object's field (NOT a type)
 type ObjectField interface {
+	Locator
 	Namer
 	Value() Value
 	SetValue(Value)
 }
 
 type objectField struct {
+	locationComponent
 	nameComponent
 	valueComponent
 }
@@ -300,33 +507,75 @@ type ObjectValue interface {
 }
 
 type objectValue struct {
+	locationComponent
 	fields ObjectFieldList
 }
 
+// ListValue represents a literal list, e.g. `[1, 2, 3]`
+type ListValue interface {
+	Value
+
+	Values() chan Value
+	AddValues(...Value)
+}
+
+type listValue struct {
+	locationComponent
+	values ValueList
+}
+
 type Selection interface{}
 
 type Argument interface {
+	Locator
 	Namer
 	Value() Value
 }
 
 type argument struct {
+	locationComponent
 	nameComponent
 	valueComponent
 }
 
 type Directive interface {
+	Locator
 	Namer
 	Arguments() chan Argument
 	AddArguments(...Argument)
 }
 
 type directive struct {
+	locationComponent
 	name      string
 	arguments ArgumentList
 }
 
+// DirectiveDefinition is a definition of a directive, declaring the
+// arguments that it accepts and the locations where it may be used
+type DirectiveDefinition interface {
+	Locator
+	Describer
+	Namer
+	Arguments() chan ObjectFieldArgumentDefinition
+	AddArguments(...ObjectFieldArgumentDefinition)
+	Locations() []DirectiveLocation
+	AddLocations(...DirectiveLocation)
+	IsRepeatable() bool
+	SetRepeatable(bool)
+}
+
+type directiveDefinition struct {
+	locationComponent
+	descriptionComponent
+	nameComponent
+	arguments  ObjectFieldArgumentDefinitionList
+	locations  []DirectiveLocation
+	repeatable bool
+}
+
 type SelectionField interface {
+	Locator
 	Namer
 	DirectivesContainer
 	SelectionsContainer
@@ -339,6 +588,7 @@ type SelectionField interface {
 }
 
 type selectionField struct {
+	locationComponent
 	nameComponent
 	hasAlias   bool
 	alias      string
@@ -348,16 +598,19 @@ type selectionField struct {
 }
 
 type FragmentSpread interface {
+	Locator
 	Namer
 	DirectivesContainer
 }
 
 type fragmentSpread struct {
+	locationComponent
 	nameComponent
 	directives DirectiveList
 }
 
 type InlineFragment interface {
+	Locator
 	DirectivesContainer
 	SelectionsContainer
 
@@ -366,39 +619,175 @@ type InlineFragment interface {
 }
 
 type inlineFragment struct {
+	locationComponent
 	directives DirectiveList
 	selections SelectionList
 	typ        NamedType
 }
 
 type UnionDefinition interface {
+	Locator
+	Describer
+	DirectivesContainer
 	Namer
+	TypeResolverContainer
 	Types() chan Type
 	AddTypes(...Type)
 }
 
 type unionDefinition struct {
+	locationComponent
+	descriptionComponent
+	directivesComponent
 	nameComponent
+	typeResolverComponent
 	types TypeList
 }
 
 type Schema interface {
+	Locator
 	Namer
+	Describer
+	DirectivesContainer
 	Query() NamedType
 	SetQuery(NamedType)
 	Mutation() NamedType
 	SetMutation(NamedType)
 	Subscription() NamedType
 	SetSubscription(NamedType)
+
+	// Types and AddTypes handle the non-standard `types: [...]` key
 	Types() chan NamedType
 	AddTypes(...NamedType)
-	Directives() chan string
-	AddDirectives(...string)
 }
 
 type schema struct {
+	locationComponent
+	descriptionComponent
 	query        NamedType
 	types        NamedTypeList
 	mutation     NamedType
 	subscription NamedType
+	directives   DirectiveList
+}
+
+// Extension is implemented by the definitions that extend an existing
+// type or schema (`extend type`, `extend schema`, etc), as opposed to
+// defining a new one. Extensions are not registered as types in the
+// document they belong to: use MergeExtensions to apply them to the
+// definitions that they extend
+type Extension interface {
+	Definition
+	Locator
+	DirectivesContainer
+	IsExtension() bool
+}
+
+type ObjectExtension interface {
+	Extension
+	AddFields(...ObjectFieldDefinition)
+	Fields() chan ObjectFieldDefinition
+	Implementer
+}
+
+type objectExtension struct {
+	locationComponent
+	directivesComponent
+	extensionComponent
+	nameComponent
+	interfacesComponent
+	fields ObjectFieldDefinitionList
+}
+
+type InterfaceExtension interface {
+	Extension
+	Implementer
+	Fields() chan InterfaceFieldDefinition
+	AddFields(...InterfaceFieldDefinition)
+}
+
+type interfaceExtension struct {
+	locationComponent
+	directivesComponent
+	extensionComponent
+	nameComponent
+	interfacesComponent
+	fields InterfaceFieldDefinitionList
+}
+
+type UnionExtension interface {
+	Extension
+	Types() chan Type
+	AddTypes(...Type)
+}
+
+type unionExtension struct {
+	locationComponent
+	directivesComponent
+	extensionComponent
+	nameComponent
+	types TypeList
+}
+
+type EnumExtension interface {
+	Extension
+	Elements() chan EnumElementDefinition
+	AddElements(...EnumElementDefinition)
+}
+
+type enumExtension struct {
+	locationComponent
+	directivesComponent
+	extensionComponent
+	nameComponent
+	elements EnumElementDefinitionList
+}
+
+type InputExtension interface {
+	Extension
+	Fields() chan InputFieldDefinition
+	AddFields(...InputFieldDefinition)
+}
+
+type inputExtension struct {
+	locationComponent
+	directivesComponent
+	extensionComponent
+	nameComponent
+	fields InputFieldDefinitionList
+}
+
+// ScalarExtension adds directives to a scalar type. It has no methods
+// beyond those of Extension, so any extension satisfies it: check for
+// it after the other kinds of extensions in type switches
+type ScalarExtension interface {
+	Extension
+}
+
+type scalarExtension struct {
+	locationComponent
+	directivesComponent
+	extensionComponent
+	nameComponent
+}
+
+// SchemaExtension adds directives and root operation types to the
+// schema definition. Root operation types that are not extended are nil
+type SchemaExtension interface {
+	Extension
+	Query() NamedType
+	SetQuery(NamedType)
+	Mutation() NamedType
+	SetMutation(NamedType)
+	Subscription() NamedType
+	SetSubscription(NamedType)
+}
+
+type schemaExtension struct {
+	locationComponent
+	directivesComponent
+	extensionComponent
+	query        NamedType
+	mutation     NamedType
+	subscription NamedType
 }
@@ -92,6 +92,21 @@ func (v TypeList) Iterator() chan Type {
 	return ch
 }
 
+type ValueList []Value
+
+func (l *ValueList) Add(list ...Value) {
+	*l = append(*l, list...)
+}
+
+func (v ValueList) Iterator() chan Value {
+	ch := make(chan Value, len(v))
+	for _, e := range v {
+		ch <- e
+	}
+	close(ch)
+	return ch
+}
+
 type VariableDefinitionList []VariableDefinition
 
 func (l *VariableDefinitionList) Add(list ...VariableDefinition) {
@@ -0,0 +1,19 @@
+package model
+
+import "golang.org/x/net/context"
+
+// ResolverFunc is an adapter to allow the use of ordinary functions
+// as a Resolver
+type ResolverFunc func(interface{}) Type
+
+func (f ResolverFunc) Resolve(v interface{}) Type {
+	return f(v)
+}
+
+// FieldResolverFunc is an adapter to allow the use of ordinary functions
+// as a FieldResolver
+type FieldResolverFunc func(context.Context, interface{}, map[string]interface{}) (interface{}, error)
+
+func (f FieldResolverFunc) ResolveField(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+	return f(ctx, source, args)
+}
@@ -0,0 +1,320 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// SerializeFunc converts a value produced by a resolver into the
+// representation of a scalar used in the result
+type SerializeFunc func(interface{}) (interface{}, error)
+
+// ParseValueFunc converts a value provided as a variable into the
+// representation of a scalar used by resolvers
+type ParseValueFunc func(interface{}) (interface{}, error)
+
+// ParseLiteralFunc converts a literal value found in a document into
+// the representation of a scalar used by resolvers
+type ParseLiteralFunc func(Value) (interface{}, error)
+
+func NewScalarDefinition(name string) ScalarDefinition {
+	return &scalarDefinition{
+		nameComponent: nameComponent(name),
+		nullable:      nullable(true),
+	}
+}
+
+func (def *scalarDefinition) SetSerializeFunc(f SerializeFunc) {
+	def.serialize = f
+}
+
+func (def *scalarDefinition) SetParseValueFunc(f ParseValueFunc) {
+	def.parseValue = f
+}
+
+func (def *scalarDefinition) SetParseLiteralFunc(f ParseLiteralFunc) {
+	def.parseLiteral = f
+}
+
+func (def *scalarDefinition) Serialize(v interface{}) (interface{}, error) {
+	if def.serialize == nil {
+		return v, nil
+	}
+	return def.serialize(v)
+}
+
+func (def *scalarDefinition) ParseValue(v interface{}) (interface{}, error) {
+	if def.parseValue == nil {
+		return v, nil
+	}
+	return def.parseValue(v)
+}
+
+func (def *scalarDefinition) ParseLiteral(v Value) (interface{}, error) {
+	if def.parseLiteral == nil {
+		return literalValue(v)
+	}
+	return def.parseLiteral(v)
+}
+
+// literalValue converts a constant literal value into its Go
+// representation without any knowledge of the expected type
+func literalValue(v Value) (interface{}, error) {
+	switch v.Kind() {
+	case VariableKind:
+		return nil, errors.Errorf(`unexpected variable $%s`, v.Value())
+	case ListKind:
+		list := []interface{}{}
+		for elem := range v.(ListValue).Values() {
+			ev, err := literalValue(elem)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, ev)
+		}
+		return list, nil
+	case ObjectKind:
+		m := make(map[string]interface{})
+		for f := range v.(ObjectValue).Fields() {
+			fv, err := literalValue(f.Value())
+			if err != nil {
+				return nil, err
+			}
+			m[f.Name()] = fv
+		}
+		return m, nil
+	default:
+		return v.Value(), nil
+	}
+}
+
+var builtinScalars = make(map[string]*scalarDefinition)
+
+func init() {
+	for _, spec := range []struct {
+		name         string
+		description  string
+		serialize    SerializeFunc
+		parseValue   ParseValueFunc
+		parseLiteral ParseLiteralFunc
+	}{
+		{
+			name:         "Int",
+			description:  "The `Int` scalar type represents non-fractional signed whole numeric values.",
+			serialize:    coerceInt,
+			parseValue:   coerceInt,
+			parseLiteral: parseIntLiteral,
+		},
+		{
+			name:         "Float",
+			description:  "The `Float` scalar type represents signed double-precision fractional values.",
+			serialize:    coerceFloat,
+			parseValue:   coerceFloat,
+			parseLiteral: parseFloatLiteral,
+		},
+		{
+			name:         "String",
+			description:  "The `String` scalar type represents textual data, represented as UTF-8 character sequences.",
+			serialize:    serializeString,
+			parseValue:   parseStringValue,
+			parseLiteral: parseStringLiteral,
+		},
+		{
+			name:         "Boolean",
+			description:  "The `Boolean` scalar type represents `true` or `false`.",
+			serialize:    coerceBoolean,
+			parseValue:   coerceBoolean,
+			parseLiteral: parseBooleanLiteral,
+		},
+		{
+			name:         "ID",
+			description:  "The `ID` scalar type represents a unique identifier, serialized in the same way as a String.",
+			serialize:    serializeString,
+			parseValue:   parseIDValue,
+			parseLiteral: parseIDLiteral,
+		},
+	} {
+		def := &scalarDefinition{
+			nameComponent: nameComponent(spec.name),
+			nullable:      nullable(true),
+			serialize:     spec.serialize,
+			parseValue:    spec.parseValue,
+			parseLiteral:  spec.parseLiteral,
+		}
+		def.SetDescription(spec.description)
+		builtinScalars[spec.name] = def
+	}
+}
+
+// BuiltinScalars returns the definitions of the scalars that are
+// provided by GraphQL itself (Int, Float, String, Boolean and ID),
+// sorted by name. The definitions are copies, so modifying them does
+// not change the built-in scalars
+func BuiltinScalars() []ScalarDefinition {
+	names := make([]string, 0, len(builtinScalars))
+	for name := range builtinScalars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]ScalarDefinition, len(names))
+	for i, name := range names {
+		list[i] = builtinScalars[name].clone()
+	}
+	return list
+}
+
+// LookupBuiltinScalar returns a copy of the definition of the
+// built-in scalar `name`, if there is one
+func LookupBuiltinScalar(name string) (ScalarDefinition, bool) {
+	def, ok := builtinScalars[name]
+	if !ok {
+		return nil, false
+	}
+	return def.clone(), true
+}
+
+func (def *scalarDefinition) clone() *scalarDefinition {
+	c := *def
+	c.directives = append(DirectiveList(nil), def.directives...)
+	return &c
+}
+
+// coerceInt accepts integral values that can be represented as signed
+// 32-bit integers, as required for Int by the specification
+func coerceInt(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i := rv.Int(); i < math.MinInt32 || i > math.MaxInt32 {
+			return nil, errors.Errorf(`Int can not represent %d: out of range`, i)
+		}
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := rv.Uint(); u > math.MaxInt32 {
+			return nil, errors.Errorf(`Int can not represent %d: out of range`, u)
+		}
+		return int(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f != math.Trunc(f) {
+			return nil, errors.Errorf(`Int can not represent non-integral value %v`, f)
+		}
+		if f < math.MinInt32 || f > math.MaxInt32 {
+			return nil, errors.Errorf(`Int can not represent %v: out of range`, f)
+		}
+		return int(f), nil
+	}
+	return nil, errors.Errorf(`can not convert %T to Int`, v)
+}
+
+func coerceFloat(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return nil, errors.Errorf(`can not convert %T to Float`, v)
+}
+
+func coerceBoolean(v interface{}) (interface{}, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Bool {
+		return rv.Bool(), nil
+	}
+	return nil, errors.Errorf(`can not convert %T to Boolean`, v)
+}
+
+// serializeString is used by both String and ID, which accept
+// integers and fmt.Stringers in addition to strings
+func serializeString(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(v), nil
+	}
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String(), nil
+	}
+	return nil, errors.Errorf(`can not convert %T to String`, v)
+}
+
+func parseStringValue(v interface{}) (interface{}, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	return nil, errors.Errorf(`can not convert %T to String`, v)
+}
+
+// parseIDValue accepts strings and integers. Integral numbers decoded
+// from JSON are float64 values, so they are accepted as well
+func parseIDValue(v interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprint(v), nil
+	case reflect.Float32, reflect.Float64:
+		if f := rv.Float(); f == math.Trunc(f) {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+	}
+	return nil, errors.Errorf(`can not convert %T to ID`, v)
+}
+
+func parseIntLiteral(v Value) (interface{}, error) {
+	if v.Kind() != IntKind {
+		return nil, errors.Errorf(`expected Int literal, got %s`, v.Kind())
+	}
+	if i := v.Value().(int); i < math.MinInt32 || i > math.MaxInt32 {
+		return nil, errors.Errorf(`Int can not represent %d: out of range`, i)
+	}
+	return v.Value(), nil
+}
+
+func parseFloatLiteral(v Value) (interface{}, error) {
+	switch v.Kind() {
+	case IntKind:
+		return float64(v.Value().(int)), nil
+	case FloatKind:
+		return v.Value(), nil
+	}
+	return nil, errors.Errorf(`expected Float literal, got %s`, v.Kind())
+}
+
+func parseStringLiteral(v Value) (interface{}, error) {
+	if v.Kind() != StringKind {
+		return nil, errors.Errorf(`expected String literal, got %s`, v.Kind())
+	}
+	return v.Value(), nil
+}
+
+func parseBooleanLiteral(v Value) (interface{}, error) {
+	if v.Kind() != BooleanKind {
+		return nil, errors.Errorf(`expected Boolean literal, got %s`, v.Kind())
+	}
+	return v.Value(), nil
+}
+
+func parseIDLiteral(v Value) (interface{}, error) {
+	switch v.Kind() {
+	case StringKind:
+		return v.Value(), nil
+	case IntKind:
+		return strconv.Itoa(v.Value().(int)), nil
+	}
+	return nil, errors.Errorf(`expected ID literal, got %s`, v.Kind())
+}
@@ -17,19 +17,19 @@ func (s *schema) SetQuery(q NamedType) {
 }
 
 func (s schema) Mutation() NamedType {
-	return s.query
+	return s.mutation
 }
 
 func (s *schema) SetMutation(q NamedType) {
-	s.query = q
+	s.mutation = q
 }
 
 func (s schema) Subscription() NamedType {
-	return s.query
+	return s.subscription
 }
 
 func (s *schema) SetSubscription(q NamedType) {
-	s.query = q
+	s.subscription = q
 }
 
 func (s *schema) Types() chan NamedType {
@@ -40,12 +40,12 @@ func (s *schema) AddTypes(list ...NamedType) {
 	s.types.Add(list...)
 }
 
-// TODO later: fix type
-func (s *schema) Directives() chan string {
-	return nil
+func (s *schema) Directives() chan Directive {
+	return s.directives.Iterator()
 }
 
-func (s *schema) AddDirectives(list ...string) {
+func (s *schema) AddDirectives(list ...Directive) {
+	s.directives.Add(list...)
 }
 
 func NewNamedType(name string) NamedType {
@@ -62,6 +62,42 @@ func NewListType(t Type) ListType {
 	}
 }
 
+// IsNullable returns true if `t` accepts null values. Types that do
+// not implement Nullable are considered nullable
+func IsNullable(t Type) bool {
+	if n, ok := t.(Nullable); ok {
+		return n.IsNullable()
+	}
+	return true
+}
+
+// TypeName returns the name of the named type at the bottom of `t`,
+// unwrapping any list types
+func TypeName(t Type) string {
+	switch t.(type) {
+	case ListType:
+		return TypeName(t.(ListType).Type())
+	case Namer:
+		return t.(Namer).Name()
+	}
+	return ""
+}
+
+// TypeString returns the GraphQL notation for `t`, e.g. "[String!]"
+func TypeString(t Type) string {
+	var s string
+	if lt, ok := t.(ListType); ok {
+		s = "[" + TypeString(lt.Type()) + "]"
+	} else {
+		s = TypeName(t)
+	}
+
+	if !IsNullable(t) {
+		s += "!"
+	}
+	return s
+}
+
 func NewObjectFieldArgumentDefinition(name string, typ Type) ObjectFieldArgumentDefinition {
 	return &objectFieldArgumentDefinition{
 		nameComponent: nameComponent(name),
@@ -91,19 +127,6 @@ func NewObjectFieldDefinition(name string, typ Type) ObjectFieldDefinition {
 	}
 }
 
-func (t *objectDefinition) SetImplements(typ NamedType) {
-	t.hasImplements = true
-	t.implements = typ
-}
-
-func (t objectDefinition) HasImplements() bool {
-	return t.hasImplements
-}
-
-func (t objectDefinition) Implements() NamedType {
-	return t.implements
-}
-
 func (t *objectFieldDefinition) AddArguments(list ...ObjectFieldArgumentDefinition) {
 	t.arguments.Add(list...)
 }
@@ -141,13 +164,6 @@ func NewInterfaceDefinition(name string) InterfaceDefinition {
 	}
 }
 
-type Resolver interface {
-	Resolve(interface{}) Type
-}
-
-func (iface *interfaceDefinition) SetTypeResolver(v Resolver) {}
-func (iface *interfaceDefinition) TypeResolver() Resolver     { return nil }
-
 func (iface interfaceDefinition) Fields() chan InterfaceFieldDefinition {
 	return iface.fields.Iterator()
 }
@@ -167,6 +183,14 @@ func (f *interfaceFieldDefinition) Type() Type {
 	return f.typ
 }
 
+func (f *interfaceFieldDefinition) AddArguments(list ...ObjectFieldArgumentDefinition) {
+	f.arguments.Add(list...)
+}
+
+func (f interfaceFieldDefinition) Arguments() chan ObjectFieldArgumentDefinition {
+	return f.arguments.Iterator()
+}
+
 func NewUnionDefinition(name string) UnionDefinition {
 	return &unionDefinition{
 		nameComponent: nameComponent(name),
@@ -92,9 +92,8 @@ func (v boolValue) Kind() Kind {
 	return BooleanKind
 }
 
-var nullv nullValue
 func NullValue() Value {
-	return &nullv
+	return &nullValue{}
 }
 
 func (v nullValue) Value() interface{} {
@@ -146,3 +145,25 @@ func (o *objectValue) AddFields(f ...ObjectField) {
 func (o objectValue) Value() interface{} {
 	return nil
 }
+
+func NewListValue() ListValue {
+	return &listValue{}
+}
+
+func (l listValue) Kind() Kind {
+	return ListKind
+}
+
+// Values returns the elements of the list
+func (l *listValue) Values() chan Value {
+	return l.values.Iterator()
+}
+
+func (l *listValue) AddValues(v ...Value) {
+	l.values.Add(v...)
+}
+
+// Value always returns nil. Use Values() to access the elements
+func (l listValue) Value() interface{} {
+	return nil
+}
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// SyntaxError describes a syntax error found while parsing a document.
+// When parsing stops at the first error, it can be retrieved from the
+// returned error with errors.Cause
+type SyntaxError struct {
+	// Message describes the problem, without its position
+	Message string
+
+	// Token is the token at which the problem was found
+	Token Token
+
+	// Expected lists the types of the tokens that would have been
+	// accepted instead of Token. It is empty if they are not known,
+	// e.g. when a particular keyword was expected
+	Expected []TokenType
+
+	// Source is the name of the source, as given by WithSourceName
+	Source string
+
+	// Frame shows the line of the source that contains Token, with a
+	// caret pointing to the position of the token
+	Frame string
+}
+
+// SyntaxErrors is the list of syntax errors found while parsing a
+// document with error recovery enabled
+type SyntaxErrors []*SyntaxError
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf(`%s at line %d, column %d`, e.Message, e.Token.Pos.Line, e.Token.Pos.Column)
+}
+
+func (e SyntaxErrors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no syntax errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	var buf bytes.Buffer
+	for i, err := range e {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+// Limit identifies one of the limits that can be set on the Parser
+type Limit int
+
+const (
+	LimitTokens     Limit = iota + 1 // see WithMaxTokens
+	LimitDepth                       // see WithMaxDepth
+	LimitSourceSize                  // see WithMaxSourceSize
+)
+
+func (l Limit) String() string {
+	switch l {
+	case LimitTokens:
+		return "tokens"
+	case LimitDepth:
+		return "depth"
+	case LimitSourceSize:
+		return "source size"
+	}
+	return "unknown"
+}
+
+// LimitError is returned when parsing stops because the document
+// exceeds one of the limits set on the Parser
+type LimitError struct {
+	Limit Limit
+
+	// Max is the value of the limit that was exceeded
+	Max int
+
+	// Pos is the position at which the limit was exceeded. Only the
+	// offset is known when the source size limit is exceeded
+	Pos Position
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case LimitTokens:
+		return fmt.Sprintf(`document exceeds the maximum of %d tokens at line %d, column %d`, e.Max, e.Pos.Line, e.Pos.Column)
+	case LimitDepth:
+		return fmt.Sprintf(`document exceeds the maximum depth of %d at line %d, column %d`, e.Max, e.Pos.Line, e.Pos.Column)
+	}
+	return fmt.Sprintf(`source exceeds the maximum size of %d bytes`, e.Max)
+}
+
+func syntaxErr(tok *Token, message string, args ...interface{}) error {
+	return &SyntaxError{
+		Message: fmt.Sprintf(message, args...),
+		Token:   *tok,
+	}
+}
+
+func unexpectedToken(tok *Token, message string, expected ...TokenType) error {
+	var value string
+	if len(tok.Value) > 0 {
+		value = " (" + tok.Value + ")"
+	}
+	if len(expected) == 0 {
+		return syntaxErr(tok, "%s: unexpected token %s%s", message, tok.Type, value)
+	}
+	return &SyntaxError{
+		Message:  fmt.Sprintf("%s: expected token %s, but got %s%s", message, expected, tok.Type, value),
+		Token:    *tok,
+		Expected: expected,
+	}
+}
+
+func unexpectedName(tok *Token, message string, expected ...string) error {
+	// XXX tok must be tok.Type == NAME
+	if len(expected) == 0 {
+		return syntaxErr(tok, "%s: unexpected name %s", message, tok.Value)
+	}
+	return syntaxErr(tok, "%s: expected name %v, but got %s", message, expected, tok.Value)
+}
+
+// codeFrame returns the line of the source of `l` that contains `pos`,
+// prefixed with its line number, followed by a line with a caret under
+// the column of `pos`. For example:
+//
+//	2 |   hero(id: ) {
+//	  |            ^
+//
+// Returns an empty string if the line is no longer available
+func codeFrame(l *Lexer, pos Position) string {
+	if pos.Line < 1 {
+		return ""
+	}
+
+	line, offset, ok := l.line(pos)
+	if !ok {
+		return ""
+	}
+	line = bytes.TrimRight(line, "\r")
+
+	gutter := strconv.Itoa(pos.Line)
+	var buf bytes.Buffer
+	buf.WriteString(gutter)
+	buf.WriteString(" | ")
+	buf.Write(line)
+	buf.WriteByte('\n')
+	buf.Write(bytes.Repeat([]byte{' '}, len(gutter)))
+	buf.WriteString(" | ")
+
+	// tabs are kept so that the caret lines up with the source
+	for prefix := line[:pos.Offset-offset]; len(prefix) > 0; {
+		r, w := utf8.DecodeRune(prefix)
+		if r == '\t' {
+			buf.WriteByte('\t')
+		} else {
+			buf.WriteByte(' ')
+		}
+		prefix = prefix[w:]
+	}
+	buf.WriteByte('^')
+	return buf.String()
+}
@@ -1,12 +1,18 @@
 package parser
 
 import (
+	"bytes"
+	"io"
 	"unicode"
 	"unicode/utf8"
 )
 
 const eof = rune(0)
 
+// readBufferSize is the number of bytes that a Lexer created by
+// NewReaderLexer reads from its source at once
+const readBufferSize = 32 * 1024
+
 type Position struct {
 	Offset int
 	Line   int
@@ -16,7 +22,8 @@ type Position struct {
 type Token struct {
 	Type  TokenType
 	Value string
-	Pos   Position
+	Pos   Position // position of the first character of the token
+	End   Position // position right after the last character of the token
 }
 
 type lrune struct {
@@ -25,12 +32,28 @@ type lrune struct {
 }
 
 type Lexer struct {
-	input     []byte
-	maxpos    int
-	peekCount int
-	peekRunes [3]lrune
-	cur       Position
-	start     Position
+	input        []byte
+	base         int // offset in the source of input[0]
+	maxpos       int // offset in the source of the end of input
+	src          io.Reader
+	err          error
+	peekCount    int
+	peekRunes    [3]lrune
+	cur          Position
+	start        Position
+	keepComments bool
+}
+
+// LexerOption configures the Lexer
+type LexerOption func(*Lexer)
+
+// WithComments specifies if comments (`# ...`) should be emitted as
+// COMMENT tokens. By default comments are skipped like whitespace,
+// but tools such as formatters may want to preserve them
+func WithComments(b bool) LexerOption {
+	return func(l *Lexer) {
+		l.keepComments = b
+	}
 }
 
 func (l *Lexer) emit(tok *Token, tt TokenType) bool {
@@ -41,10 +64,13 @@ func (l *Lexer) emit(tok *Token, tt TokenType) bool {
 
 	if tt != IGNORABLE {
 		tok.Type = tt
-		tok.Value = string(l.input[l.start.Offset : l.cur.Offset-peekOffset])
+		tok.Value = string(l.input[l.start.Offset-l.base : l.cur.Offset-peekOffset-l.base])
 		tok.Pos.Offset = l.start.Offset
 		tok.Pos.Line = l.start.Line
 		tok.Pos.Column = l.start.Column
+		tok.End.Offset = l.cur.Offset - peekOffset
+		tok.End.Line = l.cur.Line
+		tok.End.Column = l.cur.Column
 	}
 
 	l.start.Offset = l.cur.Offset - peekOffset
@@ -62,7 +88,7 @@ func (l *Lexer) advance() {
 	switch r := l.peek(); r {
 	case '\n':
 		l.cur.Line++
-		l.cur.Column = 0
+		l.cur.Column = 1
 	case eof:
 	default:
 		l.cur.Column++
@@ -87,11 +113,15 @@ func (l *Lexer) peek() rune {
 		return l.peekRunes[l.peekCount].r
 	}
 
+	if l.src != nil && l.maxpos-l.cur.Offset < utf8.UTFMax {
+		l.fill()
+	}
+
 	if l.cur.Offset >= l.maxpos {
 		return eof
 	}
 
-	r, w := utf8.DecodeRune(l.input[l.cur.Offset:])
+	r, w := utf8.DecodeRune(l.input[l.cur.Offset-l.base:])
 	l.peekCount++
 	l.peekRunes[l.peekCount].r = r
 	l.peekRunes[l.peekCount].w = w
@@ -100,8 +130,11 @@ func (l *Lexer) peek() rune {
 	return r
 }
 
-func NewLexer(src []byte) *Lexer {
+func NewLexer(src []byte, options ...LexerOption) *Lexer {
 	l := &Lexer{}
+	for _, option := range options {
+		option(l)
+	}
 	l.input = src
 	l.maxpos = len(src)
 	l.cur.Offset = 0
@@ -112,6 +145,82 @@ func NewLexer(src []byte) *Lexer {
 	return l
 }
 
+// NewReaderLexer creates a Lexer that reads the source from `r` as
+// it goes. Only the part of the source starting at the line of the
+// current token is kept in memory. The tokens and their positions are
+// the same as those produced by NewLexer for the whole source
+func NewReaderLexer(r io.Reader, options ...LexerOption) *Lexer {
+	l := NewLexer(make([]byte, 0, readBufferSize), options...)
+	l.src = r
+	return l
+}
+
+// fill reads more of the source into the buffer, until a complete
+// rune is available at the current position or the end of the source
+// is reached. The part of the buffer before the line of the current
+// token is discarded, unless the line is too long. The new line that
+// precedes the line is kept, so that the start of the line is known
+func (l *Lexer) fill() {
+	keep := l.start.Offset - l.base
+	if i := bytes.LastIndexByte(l.input[:keep], '\n'); keep-i <= readBufferSize {
+		keep = i
+	}
+	if keep > 0 {
+		n := copy(l.input, l.input[keep:])
+		l.input = l.input[:n]
+		l.base += keep
+	}
+
+	for l.src != nil && l.base+len(l.input)-l.cur.Offset < utf8.UTFMax {
+		if len(l.input) == cap(l.input) {
+			buf := make([]byte, len(l.input), 2*cap(l.input))
+			copy(buf, l.input)
+			l.input = buf
+		}
+
+		n, err := l.src.Read(l.input[len(l.input):cap(l.input)])
+		l.input = l.input[:len(l.input)+n]
+		if err != nil {
+			if err != io.EOF {
+				l.err = err
+			}
+			l.src = nil
+		}
+	}
+	l.maxpos = l.base + len(l.input)
+}
+
+// Err returns the error that occurred while reading the source of a
+// Lexer created by NewReaderLexer, if any. The source is treated as
+// if it ended where the error occurred
+func (l *Lexer) Err() error {
+	return l.err
+}
+
+// line returns the part of the line containing the position `pos`
+// that is still available to the lexer, and the offset of the start
+// of the line in the source. Returns false if the start of the line
+// is no longer available
+func (l *Lexer) line(pos Position) ([]byte, int, bool) {
+	if pos.Offset < l.base || pos.Offset > l.maxpos {
+		return nil, 0, false
+	}
+
+	offset := pos.Offset - l.base
+	start := bytes.LastIndexByte(l.input[:offset], '\n') + 1
+	if start == 0 && l.base > 0 {
+		return nil, 0, false
+	}
+
+	end := bytes.IndexByte(l.input[offset:], '\n')
+	if end < 0 {
+		end = len(l.input)
+	} else {
+		end += offset
+	}
+	return l.input[start:end], l.base + start, true
+}
+
 func (l *Lexer) Next(tok *Token) bool {
 	l.skipInsignificant()
 
@@ -151,6 +260,9 @@ func (l *Lexer) Next(tok *Token) bool {
 	case '|':
 		l.advance()
 		return l.emit(tok, PIPE)
+	case '&':
+		l.advance()
+		return l.emit(tok, AMP)
 	case '}':
 		l.advance()
 		return l.emit(tok, BRACE_R)
@@ -164,6 +276,10 @@ func (l *Lexer) Next(tok *Token) bool {
 			return l.emit(tok, ILLEGAL)
 		}
 		return l.emit(tok, STRING)
+	case '#':
+		// only reachable when comments are kept
+		l.runComment()
+		return l.emit(tok, COMMENT)
 	default:
 		typ, ok := l.lexValue()
 		if !ok {
@@ -177,15 +293,8 @@ func (l *Lexer) Next(tok *Token) bool {
 func (l *Lexer) lexValue() (TokenType, bool) {
 	r := l.peek()
 	switch {
-	case unicode.IsDigit(r):
+	case unicode.IsDigit(r), r == '-' || r == '+':
 		return l.lexNumber()
-	case r == '-' || r == '+':
-		l.advance()
-		if unicode.IsDigit(l.peek()) {
-			l.rewind()
-			return l.lexNumber()
-		}
-		return ILLEGAL, false
 	case r == '"':
 		return l.lexString()
 	default:
@@ -216,10 +325,9 @@ func (l *Lexer) lexString() (TokenType, bool) {
 }
 
 func (l *Lexer) lexNumber() (TokenType, bool) {
-	r := l.next()
-	switch r {
+	switch l.peek() {
 	case '-', '+':
-		r = l.next()
+		l.advance()
 	}
 
 	var typ TokenType
@@ -240,10 +348,9 @@ func (l *Lexer) lexNumber() (TokenType, bool) {
 	case 'e', 'E':
 		typ = FLOAT
 		l.advance()
-		switch l.next() {
+		switch l.peek() {
 		case '-', '+':
-		default:
-			return ILLEGAL, false
+			l.advance()
 		}
 		if !l.runDigits() {
 			return ILLEGAL, false
@@ -258,6 +365,12 @@ func (l *Lexer) skipInsignificant() {
 		switch l.peek() {
 		case '\t', ' ', '\n', '\r', ',':
 			l.advance()
+		case '#':
+			if l.keepComments {
+				l.emit(nil, IGNORABLE)
+				return
+			}
+			l.runComment()
 		default:
 			l.emit(nil, IGNORABLE)
 			return
@@ -265,6 +378,21 @@ func (l *Lexer) skipInsignificant() {
 	}
 }
 
+// # followed by anything up to the end of the line
+func (l *Lexer) runComment() bool {
+	if l.next() != '#' {
+		return false
+	}
+
+	for {
+		switch l.peek() {
+		case '\n', '\r', eof:
+			return true
+		}
+		l.advance()
+	}
+}
+
 // ...
 func (l *Lexer) runSpread() bool {
 	for i := 0; i < 3; i++ {
@@ -301,11 +429,22 @@ func (l *Lexer) runName() bool {
 	return false
 }
 
+// "..." or """..."""
 func (l *Lexer) runString() bool {
 	if l.next() != '"' {
 		return false
 	}
 
+	if l.peek() == '"' {
+		l.advance()
+		if l.peek() != '"' {
+			// empty string
+			return true
+		}
+		l.advance()
+		return l.runBlockString()
+	}
+
 	for loop := true; loop; {
 		switch l.peek() {
 		case '"':
@@ -316,7 +455,7 @@ func (l *Lexer) runString() bool {
 			if !l.runEscapeSequence() {
 				return false
 			}
-		case '\n', '\r':
+		case '\n', '\r', eof:
 			return false
 		default:
 			l.advance()
@@ -329,6 +468,37 @@ func (l *Lexer) runString() bool {
 	return true
 }
 
+// runBlockString consumes the rest of a block string, after the
+// opening """. Only \""" is treated specially inside block strings
+func (l *Lexer) runBlockString() bool {
+	// quotes returns the number of consecutive quotes consumed, up to n
+	quotes := func(n int) int {
+		for i := 0; i < n; i++ {
+			if l.peek() != '"' {
+				return i
+			}
+			l.advance()
+		}
+		return n
+	}
+
+	for {
+		switch l.peek() {
+		case eof:
+			return false
+		case '"':
+			if quotes(3) == 3 {
+				return true
+			}
+		case '\\':
+			l.advance()
+			quotes(3)
+		default:
+			l.advance()
+		}
+	}
+}
+
 func (l *Lexer) runEscapeSequence() bool {
 	if l.next() != '\\' {
 		return false
@@ -1,7 +1,13 @@
 package parser
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -37,4 +43,117 @@ func TestLex(t *testing.T) {
 	t.Run(testlex([]byte("123.142"), FLOAT, EOF))
 	t.Run(testlex([]byte("123e+142"), FLOAT, EOF))
 	t.Run(testlex([]byte(`"Hello\u0020World"`), STRING, EOF))
+	t.Run(testlex([]byte(`""`), STRING, EOF))
+	t.Run(testlex([]byte(`"Hello`), ILLEGAL))
+	t.Run(testlex([]byte("\"Hello\nWorld\""), ILLEGAL))
+	t.Run(testlex([]byte(`"""Hello "World" \n"""`), STRING, EOF))
+	t.Run(testlex([]byte("\"\"\"\n  Hello\n    World\n\"\"\"\nfoo"), STRING, NAME, EOF))
+	t.Run(testlex([]byte(`"""Hello \""" World""" foo`), STRING, NAME, EOF))
+	t.Run(testlex([]byte(`"""Hello`), ILLEGAL))
+	t.Run(testlex([]byte("1"), INT, EOF))
+	t.Run(testlex([]byte("-1"), INT, EOF))
+	t.Run(testlex([]byte("1e10"), FLOAT, EOF))
+	t.Run(testlex([]byte("-"), ILLEGAL))
+	t.Run(testlex([]byte("# comment"), EOF))
+	t.Run(testlex([]byte("Hello # comment\nWorld"), NAME, NAME, EOF))
+	t.Run(testlex([]byte("Hello# comment\r\n#\nWorld"), NAME, NAME, EOF))
+}
+
+func TestLexComments(t *testing.T) {
+	l := NewLexer([]byte("# first\nHello # second\n#"), WithComments(true))
+
+	expected := []Token{
+		{Type: COMMENT, Value: "# first", Pos: Position{Offset: 0, Line: 1, Column: 1}},
+		{Type: NAME, Value: "Hello", Pos: Position{Offset: 8, Line: 2, Column: 1}},
+		{Type: COMMENT, Value: "# second", Pos: Position{Offset: 14, Line: 2, Column: 7}},
+		{Type: COMMENT, Value: "#", Pos: Position{Offset: 23, Line: 3, Column: 1}},
+		{Type: EOF, Pos: Position{Offset: 24, Line: 3, Column: 2}},
+	}
+
+	var tok Token
+	for i, e := range expected {
+		if !assert.True(t, l.Next(&tok), "token #%d should be read", i+1) {
+			return
+		}
+		if !assert.Equal(t, e.Type, tok.Type, "token #%d type should match", i+1) {
+			return
+		}
+		if !assert.Equal(t, e.Value, tok.Value, "token #%d value should match", i+1) {
+			return
+		}
+		if !assert.Equal(t, e.Pos, tok.Pos, "token #%d position should match", i+1) {
+			return
+		}
+	}
+}
+
+var errBrokenSource = errors.New("broken source")
+
+// errReader returns "query" followed by errBrokenSource
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return copy(p, "query"), errBrokenSource
+}
+
+// lexAll returns all the tokens read from `l`, up to and including EOF
+func lexAll(l *Lexer) []Token {
+	var tokens []Token
+	for {
+		var tok Token
+		if !l.Next(&tok) {
+			return tokens
+		}
+		tokens = append(tokens, tok)
+		if tok.Type == EOF {
+			return tokens
+		}
+	}
+}
+
+func TestReaderLexer(t *testing.T) {
+	var large bytes.Buffer
+	for i := 0; large.Len() < 16*readBufferSize; i++ {
+		fmt.Fprintf(&large, "type T%d {\n  \"é ✓\"\n  f%d(a: [Int!] = [%d, -1.5e3]): String # comment\n}\n", i, i, i)
+	}
+
+	for _, src := range []string{
+		"query {\r\n  hero(name: \"Luke ✓\") { ...Fields }\n}",
+		"\"\"\"\n  Block\n    \"\"\" é\n\"\"\"\ntype Query { a: Int }",
+		"\"unterminated",
+		strings.Repeat("x", 3*readBufferSize) + " y",
+		large.String(),
+	} {
+		src := src
+		name := src
+		if len(name) > 20 {
+			name = name[:20]
+		}
+		t.Run(name, func(t *testing.T) {
+			expected := lexAll(NewLexer([]byte(src), WithComments(true)))
+			for _, r := range []io.Reader{strings.NewReader(src), iotest.OneByteReader(strings.NewReader(src))} {
+				l := NewReaderLexer(r, WithComments(true))
+				if !assert.Equal(t, expected, lexAll(l), "tokens should match") {
+					return
+				}
+				if !assert.NoError(t, l.Err(), "l.Err should be nil") {
+					return
+				}
+				if !assert.True(t, cap(l.input) <= 4*readBufferSize, "buffer should not hold the whole source") {
+					return
+				}
+			}
+		})
+	}
+
+	t.Run("Read error", func(t *testing.T) {
+		l := NewReaderLexer(errReader{})
+		tokens := lexAll(l)
+		if !assert.Len(t, tokens, 2, "lexer should stop at the error") {
+			return
+		}
+		if !assert.Equal(t, errBrokenSource, l.Err(), "l.Err should return the read error") {
+			return
+		}
+	})
 }
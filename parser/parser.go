@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/lestrrat/go-graphql/model"
 	"github.com/pkg/errors"
@@ -9,55 +10,105 @@ import (
 )
 
 const (
-	enumKey       = "enum"
-	falseKey      = "false"
-	fragmentKey   = "fragment"
-	implementsKey = "implements"
-	inputKey      = "input"
-	interfaceKey  = "interface"
-	mutationKey   = "mutation"
-	nullKey       = "null"
-	onKey         = "on"
-	queryKey      = "query"
-	trueKey       = "true"
-	typeKey       = "type"
-	typesKey      = "types"
-	unionKey      = "union"
-	schemaKey     = "schema"
+	directiveKey    = "directive"
+	enumKey         = "enum"
+	extendKey       = "extend"
+	falseKey        = "false"
+	fragmentKey     = "fragment"
+	implementsKey   = "implements"
+	inputKey        = "input"
+	interfaceKey    = "interface"
+	mutationKey     = "mutation"
+	nullKey         = "null"
+	onKey           = "on"
+	queryKey        = "query"
+	repeatableKey   = "repeatable"
+	trueKey         = "true"
+	typeKey         = "type"
+	typesKey        = "types"
+	unionKey        = "union"
+	scalarKey       = "scalar"
+	schemaKey       = "schema"
+	subscriptionKey = "subscription"
 )
 
-type Parser struct{}
+type Parser struct {
+	sourceName    string
+	schemaTypes   bool
+	errorRecovery bool
+	maxTokens     int
+	maxDepth      int
+	maxSourceSize int
+}
+
+// Option configures the Parser
+type Option func(*Parser)
+
+// WithSourceName specifies the name of the source (e.g. a file name),
+// which is recorded in the locations of the nodes that are parsed
+func WithSourceName(name string) Option {
+	return func(p *Parser) {
+		p.sourceName = name
+	}
+}
+
+// WithSchemaTypes specifies if the non-standard `types: [...]` key
+// should be accepted in schema definitions, along with the standard
+// `query`, `mutation` and `subscription` keys. It is not accepted
+// by default
+func WithSchemaTypes(b bool) Option {
+	return func(p *Parser) {
+		p.schemaTypes = b
+	}
+}
 
-func New() *Parser {
-	return &Parser{}
+// WithErrorRecovery specifies if the parser should continue after a
+// syntax error. When enabled, the parser skips to the next definition
+// or to the next selection in the enclosing selection set, and Parse
+// returns the partial document along with all of the errors found, as
+// SyntaxErrors. It is disabled by default
+func WithErrorRecovery(b bool) Option {
+	return func(p *Parser) {
+		p.errorRecovery = b
+	}
 }
 
-func syntaxErr(tok *Token, message string, args ...interface{}) error {
-	return errors.Errorf(
-		`%s at line %d, column %d`,
-		fmt.Sprintf(message, args...),
-		tok.Pos.Line,
-		tok.Pos.Column,
-	)
+// WithMaxTokens limits the number of tokens that a document may
+// contain. Parsing stops with a *LimitError as soon as the limit is
+// exceeded. There is no limit by default
+func WithMaxTokens(n int) Option {
+	return func(p *Parser) {
+		p.maxTokens = n
+	}
 }
 
-func unexpectedToken(tok *Token, message string, expected ...TokenType) error {
-	var value string
-	if len(tok.Value) > 0 {
-		value = " (" + tok.Value + ")"
+// WithMaxDepth limits the nesting depth of selection sets, list and
+// object values, and list types, which are counted together: e.g. an
+// object value given as an argument to a field of the top level
+// selection set is at depth 2. Parsing stops with a *LimitError as
+// soon as the limit is exceeded. There is no limit by default
+func WithMaxDepth(n int) Option {
+	return func(p *Parser) {
+		p.maxDepth = n
 	}
-	if len(expected) == 0 {
-		return syntaxErr(tok, "%s: unexpected token %s%s", message, tok.Type, value)
+}
+
+// WithMaxSourceSize limits the size of the source in bytes. Sources
+// given to Parse are rejected upfront, while sources given to
+// ParseReader are read up to the limit. Either way, a *LimitError is
+// returned. There is no limit by default
+func WithMaxSourceSize(n int) Option {
+	return func(p *Parser) {
+		p.maxSourceSize = n
 	}
-	return syntaxErr(tok, "%s: expected token %s, but got %s%s", message, expected, tok.Type, value)
 }
 
-func unexpectedName(tok *Token, message string, expected ...string) error {
-	// XXX tok must be tok.Type == NAME
-	if len(expected) == 0 {
-		return syntaxErr(tok, "%s: unexpected name %s", message, tok.Value)
+func New(options ...Option) *Parser {
+	p := &Parser{}
+	for _, option := range options {
+		option(p)
 	}
-	return syntaxErr(tok, "%s: expected name %v, but got %s", message, expected, tok.Value)
+	return p
 }
 
 func consumeToken(pctx *parseCtx, typ TokenType) (*Token, error) {
@@ -65,7 +116,11 @@ func consumeToken(pctx *parseCtx, typ TokenType) (*Token, error) {
 	case typ:
 		return t, nil
 	default:
-		return nil, syntaxErr(t, `expected token %s, got %s`, typ, t.Type)
+		return nil, &SyntaxError{
+			Message:  fmt.Sprintf(`expected token %s, got %s`, typ, t.Type),
+			Token:    *t,
+			Expected: []TokenType{typ},
+		}
 	}
 }
 
@@ -110,30 +165,207 @@ func (p *Parser) ParseString(ctx context.Context, src string) (model.Document, e
 }
 
 func (p *Parser) Parse(ctx context.Context, src []byte) (model.Document, error) {
+	if p.maxSourceSize > 0 && len(src) > p.maxSourceSize {
+		return nil, &LimitError{Limit: LimitSourceSize, Max: p.maxSourceSize, Pos: Position{Offset: p.maxSourceSize}}
+	}
+	return p.parse(ctx, NewLexer(src))
+}
+
+// ParseReader parses the document read from `r`. The source is read
+// as it is being parsed, so that it does not have to be held in memory
+// as a whole. The result is the same as parsing the whole source with
+// Parse
+func (p *Parser) ParseReader(ctx context.Context, r io.Reader) (model.Document, error) {
+	if p.maxSourceSize > 0 {
+		r = &sizeLimitedReader{r: r, max: p.maxSourceSize}
+	}
+	return p.parse(ctx, NewReaderLexer(r))
+}
+
+// sizeLimitedReader reads from `r`, and fails with a *LimitError when
+// the source turns out to be larger than `max` bytes
+type sizeLimitedReader struct {
+	r   io.Reader
+	n   int
+	max int
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	// read one more byte than allowed, to tell if there are more
+	if l := r.max - r.n + 1; len(p) > l {
+		p = p[:l]
+	}
+
+	n, err := r.r.Read(p)
+	r.n += n
+	if r.n > r.max {
+		return n - (r.n - r.max), &LimitError{Limit: LimitSourceSize, Max: r.max, Pos: Position{Offset: r.max}}
+	}
+	return n, err
+}
+
+func (p *Parser) parse(ctx context.Context, lexsrc *Lexer) (model.Document, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pctx := p.newParseCtx(ctx, lexsrc)
+	doc, err := pctx.parseDocument()
+	if err := pctx.result(err, `failed to parse document`); err != nil {
+		return nil, err
+	}
+
+	if len(pctx.errors) > 0 {
+		return doc, pctx.errors
+	}
+	return doc, nil
+}
+
+// ParseValue parses `src` as a single value, such as `{ids: [1, 2]}`.
+// Variables are allowed, and are returned as variable values. The
+// whole source must consist of the value, or a *SyntaxError pointing to
+// the first extra token is returned. Error recovery does not apply
+func (p *Parser) ParseValue(ctx context.Context, src []byte) (model.Value, error) {
+	var v model.Value
+	err := p.parseFragment(ctx, src, `value`, func(pctx *parseCtx) (err error) {
+		v, err = pctx.parseValue(false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ParseConstValue is like ParseValue, but does not allow variables,
+// as in default values
+func (p *Parser) ParseConstValue(ctx context.Context, src []byte) (model.Value, error) {
+	var v model.Value
+	err := p.parseFragment(ctx, src, `constant value`, func(pctx *parseCtx) (err error) {
+		v, err = pctx.parseValue(true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ParseType parses `src` as a type reference, such as `[String!]!`.
+// The whole source must consist of the type, or a *SyntaxError
+// pointing to the first extra token is returned
+func (p *Parser) ParseType(ctx context.Context, src []byte) (model.Type, error) {
+	var typ model.Type
+	err := p.parseFragment(ctx, src, `type`, func(pctx *parseCtx) (err error) {
+		typ, err = pctx.parseType()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return typ, nil
+}
+
+// ParseSelectionSet parses `src` as a selection set, including the
+// surrounding braces, such as `{ id name }`. The whole source must
+// consist of the selection set, or a *SyntaxError pointing to the
+// first extra token is returned. Error recovery does not apply
+func (p *Parser) ParseSelectionSet(ctx context.Context, src []byte) (model.SelectionList, error) {
+	var set model.SelectionList
+	err := p.parseFragment(ctx, src, `selection set`, func(pctx *parseCtx) (err error) {
+		set, err = pctx.parseSelectionSet()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// parseFragment parses the whole of `src` with `f`. `what` describes
+// the part of a document that is being parsed, for error messages
+func (p *Parser) parseFragment(ctx context.Context, src []byte, what string, f func(*parseCtx) error) error {
+	if p.maxSourceSize > 0 && len(src) > p.maxSourceSize {
+		return &LimitError{Limit: LimitSourceSize, Max: p.maxSourceSize, Pos: Position{Offset: p.maxSourceSize}}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	pctx := p.newParseCtx(ctx, NewLexer(src))
+	pctx.errorRecovery = false
+
+	err := f(pctx)
+	if err == nil {
+		if t := pctx.peek(); t.Type != EOF {
+			err = unexpectedToken(t, `end of `+what, EOF)
+		}
+	}
+	return pctx.result(err, `failed to parse `+what)
+}
+
+func (p *Parser) newParseCtx(ctx context.Context, lexsrc *Lexer) *parseCtx {
 	var pctx parseCtx
 	pctx.Context = ctx
-	pctx.lexsrc = NewLexer(src)
+	pctx.lexsrc = lexsrc
 	pctx.peekCount = -1
 	pctx.peekTokens = [3]Token{}
 	pctx.types = make(map[string]model.NamedType)
+	pctx.sourceName = p.sourceName
+	pctx.schemaTypes = p.schemaTypes
+	pctx.errorRecovery = p.errorRecovery
+	pctx.maxTokens = p.maxTokens
+	pctx.maxDepth = p.maxDepth
+	return &pctx
+}
+
+// result returns the error to report after parsing stopped with `err`.
+// A source that could not be read, a limit that was exceeded and a
+// cancelled context are reported instead of the syntax errors caused
+// by the premature end of the document
+func (pctx *parseCtx) result(err error, message string) error {
+	if rerr := pctx.lexsrc.Err(); rerr != nil {
+		if lerr, ok := rerr.(*LimitError); ok {
+			return lerr
+		}
+		return errors.Wrap(rerr, `failed to read source`)
+	}
+
+	if pctx.err != nil {
+		return pctx.err
+	}
 
-	doc, err := pctx.parseDocument()
 	if err != nil {
-		return nil, errors.Wrap(err, `failed to parse document`)
+		pctx.syntaxError(err)
+		return errors.Wrap(err, message)
 	}
-	return doc, nil
+	return nil
 }
 
 type parseCtx struct {
 	context.Context
 
-	lexsrc     *Lexer
-	peekCount  int
-	peekTokens [3]Token
-	types      map[string]model.NamedType
+	lexsrc      *Lexer
+	peekCount   int
+	peekTokens  [3]Token
+	types       map[string]model.NamedType
+	sourceName  string
+	schemaTypes bool     // accept the legacy `types` key in schema definitions
+	end         Position // end of the last consumed token
+
+	// state used to recover from syntax errors
+	errorRecovery bool
+	errors        SyntaxErrors
+	consumed      int       // number of tokens consumed so far
+	last          TokenType // type of the last consumed token
+	braceDepth    int       // number of unclosed braces
+	parenDepth    int       // number of unclosed parentheses
+
+	// state used to enforce the limits
+	maxTokens int
+	maxDepth  int
+	tokens    int   // number of tokens read so far
+	depth     int   // current nesting depth
+	err       error // error that stopped parsing, see stop()
 }
 
 var eofToken = Token{
@@ -145,23 +377,83 @@ var eofToken = Token{
 //
 // note: we do NOT check for peekCout > 2 for efficiency.
 // if you do that, you're f*cked.
+//
+// once parsing has been stopped, an EOF token is returned so that
+// the parser unwinds quickly
 func (pctx *parseCtx) peek() *Token {
 	if pctx.peekCount < 0 {
+		if pctx.err != nil {
+			return &eofToken
+		}
+
 		select {
 		case <-pctx.Context.Done():
+			pctx.stop(pctx.Context.Err())
 			return &eofToken
 		default:
 		}
-		if !pctx.lexsrc.Next(&pctx.peekTokens[pctx.peekCount+1]) {
+
+		tok := &pctx.peekTokens[pctx.peekCount+1]
+		if !pctx.lexsrc.Next(tok) {
 			return &eofToken
 		}
+
+		if tok.Type != EOF {
+			pctx.tokens++
+			if pctx.maxTokens > 0 && pctx.tokens > pctx.maxTokens {
+				pctx.stop(&LimitError{Limit: LimitTokens, Max: pctx.maxTokens, Pos: tok.Pos})
+				return &eofToken
+			}
+		}
 		pctx.peekCount++
 	}
 	return &pctx.peekTokens[pctx.peekCount]
 }
 
+// stop stops parsing with the error `err`, which is then returned by
+// Parse instead of any syntax errors
+func (pctx *parseCtx) stop(err error) {
+	if pctx.err == nil {
+		pctx.err = err
+	}
+}
+
+// enter is called when entering a nested construct, and stops parsing
+// if the maximum depth is exceeded
+func (pctx *parseCtx) enter() error {
+	pctx.depth++
+	if pctx.maxDepth > 0 && pctx.depth > pctx.maxDepth {
+		err := &LimitError{Limit: LimitDepth, Max: pctx.maxDepth, Pos: pctx.peek().Pos}
+		pctx.stop(err)
+		return err
+	}
+	return nil
+}
+
+func (pctx *parseCtx) leave() {
+	pctx.depth--
+}
+
 func (pctx *parseCtx) advance() {
 	if pctx.peekCount >= 0 {
+		t := &pctx.peekTokens[pctx.peekCount]
+		pctx.end = t.End
+		pctx.last = t.Type
+		pctx.consumed++
+		switch t.Type {
+		case BRACE_L:
+			pctx.braceDepth++
+		case BRACE_R:
+			if pctx.braceDepth > 0 {
+				pctx.braceDepth--
+			}
+		case PAREN_L:
+			pctx.parenDepth++
+		case PAREN_R:
+			if pctx.parenDepth > 0 {
+				pctx.parenDepth--
+			}
+		}
 		pctx.peekCount--
 	}
 }
@@ -178,6 +470,16 @@ func (pctx *parseCtx) next() *Token {
 	return t
 }
 
+// location returns the location of the source text that starts at
+// `start` and ends with the last consumed token
+func (pctx *parseCtx) location(start Position) model.Location {
+	return model.Location{
+		Source: pctx.sourceName,
+		Start:  model.Position(start),
+		End:    model.Position(pctx.end),
+	}
+}
+
 func (pctx *parseCtx) registerType(t model.NamedType) error {
 	pctx.types[t.Name()] = t
 	return nil
@@ -194,74 +496,208 @@ func (pctx *parseCtx) lookupType(n string) (model.NamedType, error) {
 
 func (pctx *parseCtx) parseDocument() (model.Document, error) {
 	doc := model.NewDocument()
-	for {
-		switch t := pctx.peek(); t.Type {
-		case EOF:
-			return doc, nil
-		case BRACE_L:
-			def, err := pctx.parseOperationDefinition(true)
+	for !peekToken(pctx, EOF) {
+		mark := pctx.consumed
+		def, err := pctx.parseDefinition()
+		if err != nil {
+			if !pctx.errorRecovery {
+				return nil, err
+			}
+			pctx.syntaxError(err)
+			pctx.skipDefinition(mark)
+			continue
+		}
+		doc.AddDefinitions(def)
+	}
+	return doc, nil
+}
+
+func (pctx *parseCtx) parseDefinition() (model.Definition, error) {
+	switch t := pctx.peek(); t.Type {
+	case BRACE_L:
+		def, err := pctx.parseOperationDefinition(true)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse operation definition`)
+		}
+		return def, nil
+	case NAME:
+		switch t.Value {
+		case queryKey, mutationKey, subscriptionKey:
+			def, err := pctx.parseOperationDefinition(false)
 			if err != nil {
 				return nil, errors.Wrap(err, `failed to parse operation definition`)
 			}
-			doc.AddDefinitions(def)
-		case NAME:
-			switch t.Value {
-			case queryKey, mutationKey:
-				def, err := pctx.parseOperationDefinition(false)
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse operation definition`)
-				}
-				doc.AddDefinitions(def)
-			case fragmentKey:
-				frag, err := pctx.parseFragmentDefinition()
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse fragment definition`)
-				}
-				doc.AddDefinitions(frag)
-			case typeKey:
-				typ, err := pctx.parseObjectDefinition()
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse object type definition`)
-				}
-				doc.AddDefinitions(typ)
-			case enumKey:
-				enum, err := pctx.parseEnumDefinition()
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse enum definition`)
-				}
-				doc.AddDefinitions(enum)
-			case interfaceKey:
-				iface, err := pctx.parseInterfaceDefinition()
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse interface definition`)
-				}
-				doc.AddDefinitions(iface)
-			case unionKey:
-				union, err := pctx.parseUnionDefinition()
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse union definition`)
-				}
-				doc.AddDefinitions(union)
-			case inputKey:
-				input, err := pctx.parseInputDefinition()
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse input definition`)
+			return def, nil
+		case fragmentKey:
+			frag, err := pctx.parseFragmentDefinition()
+			if err != nil {
+				return nil, errors.Wrap(err, `failed to parse fragment definition`)
+			}
+			return frag, nil
+		case typeKey, enumKey, interfaceKey, unionKey, inputKey, scalarKey, directiveKey, schemaKey:
+			return pctx.parseTypeSystemDefinition()
+		case extendKey:
+			ext, err := pctx.parseTypeExtension()
+			if err != nil {
+				return nil, errors.Wrap(err, `failed to parse type extension`)
+			}
+			return ext, nil
+		default:
+			return nil, unexpectedName(t, `document`, queryKey, mutationKey, subscriptionKey, fragmentKey, typeKey, enumKey, interfaceKey, unionKey, inputKey, scalarKey, directiveKey, schemaKey, extendKey)
+		}
+	case STRING:
+		return pctx.parseTypeSystemDefinition()
+	default:
+		return nil, unexpectedToken(t, `document`)
+	}
+}
+
+// syntaxError returns the SyntaxError that caused `err`, after filling
+// in the name of the source and the code frame. In error recovery mode
+// the error is also recorded, unless an error has already been
+// recorded at the same position
+func (pctx *parseCtx) syntaxError(err error) *SyntaxError {
+	serr, ok := errors.Cause(err).(*SyntaxError)
+	if !ok {
+		serr = &SyntaxError{Message: err.Error(), Token: *pctx.peek()}
+	}
+	serr.Source = pctx.sourceName
+	serr.Frame = codeFrame(pctx.lexsrc, serr.Token.Pos)
+
+	if pctx.errorRecovery {
+		if l := len(pctx.errors); l == 0 || pctx.errors[l-1].Token.Pos != serr.Token.Pos {
+			pctx.errors = append(pctx.errors, serr)
+		}
+	}
+	return serr
+}
+
+// skipDefinition skips the tokens up to the start of the next
+// definition, after a syntax error has been found in a definition
+// that started when `mark` tokens had been consumed. Definitions are
+// assumed to start with a keyword outside of any braces or
+// parentheses, or with a keyword, a description or an opening brace
+// at the beginning of a line
+func (pctx *parseCtx) skipDefinition(mark int) {
+	if pctx.consumed == mark {
+		pctx.advance()
+	}
+
+	for {
+		t := pctx.peek()
+		switch t.Type {
+		case EOF:
+			return
+		case NAME, STRING, BRACE_L:
+			if t.Pos.Column == 1 && (t.Type != NAME || isDefinitionKey(t.Value)) {
+				pctx.braceDepth = 0
+				pctx.parenDepth = 0
+				return
+			}
+
+			if pctx.braceDepth == 0 && pctx.parenDepth == 0 {
+				if t.Type == NAME && isDefinitionKey(t.Value) {
+					return
 				}
-				doc.AddDefinitions(input)
-			case schemaKey:
-				schema, err := pctx.parseSchemaDefinition()
-				if err != nil {
-					return nil, errors.Wrap(err, `failed to parse schema definition`)
+				if t.Type == BRACE_L && pctx.last == BRACE_R {
+					return
 				}
-				doc.AddDefinitions(schema)
-			default:
-				return nil, unexpectedName(t, `document`, queryKey, mutationKey, fragmentKey, typeKey, enumKey, interfaceKey, unionKey)
 			}
-		default:
-			return nil, unexpectedToken(t, `document`)
 		}
+		pctx.advance()
+	}
+}
+
+// typeSystemDefinition is implemented by all the definitions that
+// parseTypeSystemDefinition may return
+type typeSystemDefinition interface {
+	model.Definition
+	model.Describer
+	model.Locator
+}
+
+// parseTypeSystemDefinition parses a type, directive or schema
+// definition, along with the description that may precede it
+func (pctx *parseCtx) parseTypeSystemDefinition() (typeSystemDefinition, error) {
+	start := pctx.peek().Pos
+	description, err := pctx.parseDescription()
+	if err != nil {
+		return nil, errors.Wrap(err, `failed to parse description`)
+	}
+
+	var def typeSystemDefinition
+	t := pctx.peek()
+	if t.Type != NAME {
+		return nil, unexpectedToken(t, `type definition`, NAME)
+	}
+
+	switch t.Value {
+	case typeKey:
+		def, err = pctx.parseObjectDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse object type definition`)
+		}
+	case enumKey:
+		def, err = pctx.parseEnumDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse enum definition`)
+		}
+	case interfaceKey:
+		def, err = pctx.parseInterfaceDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse interface definition`)
+		}
+	case unionKey:
+		def, err = pctx.parseUnionDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse union definition`)
+		}
+	case inputKey:
+		def, err = pctx.parseInputDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse input definition`)
+		}
+	case scalarKey:
+		def, err = pctx.parseScalarDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse scalar definition`)
+		}
+	case directiveKey:
+		def, err = pctx.parseDirectiveDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directive definition`)
+		}
+	case schemaKey:
+		def, err = pctx.parseSchemaDefinition()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse schema definition`)
+		}
+	default:
+		return nil, unexpectedName(t, `type definition`, typeKey, enumKey, interfaceKey, unionKey, inputKey, scalarKey, directiveKey, schemaKey)
+	}
+
+	if description != "" {
+		def.SetDescription(description)
+		def.SetLocation(pctx.location(start))
+	}
+	return def, nil
+}
+
+// parseDescription consumes the description string that may precede
+// type-system definitions, fields, arguments and enum values. Returns
+// an empty string if there is none
+func (pctx *parseCtx) parseDescription() (string, error) {
+	t := pctx.peek()
+	if t.Type != STRING {
+		return "", nil
+	}
+	pctx.advance()
+
+	s, err := stringValue(t.Value)
+	if err != nil {
+		return "", syntaxErr(t, `invalid description: %s`, err)
 	}
-	return nil, errors.New("error for now")
+	return s, nil
 }
 
 func (pctx *parseCtx) parseTypeCondition() (model.NamedType, error) {
@@ -278,7 +714,7 @@ func (pctx *parseCtx) parseTypeCondition() (model.NamedType, error) {
 
 func (pctx *parseCtx) parseFragmentName() (string, error) {
 	if peekName(pctx, onKey) {
-		return "", errors.New(`fragment name: illegal fragment name "on"`)
+		return "", syntaxErr(pctx.peek(), `fragment name: illegal fragment name "on"`)
 	}
 	return consumeName(pctx)
 }
@@ -288,6 +724,7 @@ func (pctx *parseCtx) parseFragmentName() (string, error) {
 // FragmentName:
 //   Name but not on
 func (pctx *parseCtx) parseFragmentDefinition() (model.FragmentDefinition, error) {
+	start := pctx.peek().Pos
 	t, err := consumeToken(pctx, NAME)
 	if err != nil {
 		return nil, errors.Wrap(err, `fragment definition`)
@@ -322,6 +759,7 @@ func (pctx *parseCtx) parseFragmentDefinition() (model.FragmentDefinition, error
 		return nil, errors.Wrap(err, `failed to parse selection set`)
 	}
 	fdef.AddSelections(set...)
+	fdef.SetLocation(pctx.location(start))
 
 	return fdef, nil
 }
@@ -330,8 +768,9 @@ func (pctx *parseCtx) parseFragmentDefinition() (model.FragmentDefinition, error
 //   OperationType Name? VariableDefinitions? Directives? SelectionSet
 //   SelectionSet
 // OperationType: one of
-//	 query	mutation
+//	 query	mutation	subscription
 func (pctx *parseCtx) parseOperationDefinition(implicitType bool) (model.OperationDefinition, error) {
+	start := pctx.peek().Pos
 	var optyp model.OperationType
 	if implicitType {
 		optyp = model.OperationTypeQuery
@@ -345,6 +784,8 @@ func (pctx *parseCtx) parseOperationDefinition(implicitType bool) (model.Operati
 			optyp = model.OperationTypeQuery
 		case mutationKey:
 			optyp = model.OperationTypeMutation
+		case subscriptionKey:
+			optyp = model.OperationTypeSubscription
 		default:
 			return nil, errors.Errorf(`unknown operation type '%s'`, name)
 		}
@@ -377,6 +818,7 @@ func (pctx *parseCtx) parseOperationDefinition(implicitType bool) (model.Operati
 		return nil, errors.Wrap(err, `failed to parse query selection set`)
 	}
 	def.AddSelections(selections...)
+	def.SetLocation(pctx.location(start))
 	return def, nil
 }
 
@@ -410,10 +852,11 @@ func (pctx *parseCtx) parseVariableDefinitions() (model.VariableDefinitionList,
 // Variable:
 //    $  Name
 // VariableDefinition:
-//    Variable : Type DefaultValue?
+//    Variable : Type DefaultValue? Directives?
 // DefaultValue:
 //    = Value
 func (pctx *parseCtx) parseVariableDefinition() (model.VariableDefinition, error) {
+	start := pctx.peek().Pos
 	if _, err := consumeToken(pctx, DOLLAR); err != nil {
 		return nil, errors.Wrap(err, `variable`)
 	}
@@ -435,13 +878,22 @@ func (pctx *parseCtx) parseVariableDefinition() (model.VariableDefinition, error
 	vdef := model.NewVariableDefinition(name, typ)
 	if peekToken(pctx, EQUALS) {
 		pctx.advance()
-		v, err := pctx.parseValue()
+		v, err := pctx.parseValue(true)
 		if err != nil {
 			return nil, errors.Wrap(err, `variable: failed to parse default value`)
 		}
 		vdef.SetDefaultValue(v)
 	}
 
+	if peekToken(pctx, AT) {
+		directives, err := pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `variable: failed to parse directives`)
+		}
+		vdef.AddDirectives(directives...)
+	}
+	vdef.SetLocation(pctx.location(start))
+
 	return vdef, nil
 }
 
@@ -462,6 +914,7 @@ func (pctx *parseCtx) parseVariableDefinition() (model.VariableDefinition, error
 func (pctx *parseCtx) parseType() (model.Type, error) {
 	var typ model.Type
 	var err error
+	start := pctx.peek().Pos
 	switch t := pctx.peek(); t.Type {
 	case NAME:
 		typ, err = pctx.parseNamedType()
@@ -474,7 +927,7 @@ func (pctx *parseCtx) parseType() (model.Type, error) {
 			return nil, errors.Wrap(err, `failed to parse list type`)
 		}
 	default:
-		return nil, errors.Errorf(`expected NamedType or ListType, got %s`, t.Type)
+		return nil, unexpectedToken(t, `type`, NAME, BRACKET_L)
 	}
 
 	if peekToken(pctx, BANG) {
@@ -484,17 +937,24 @@ func (pctx *parseCtx) parseType() (model.Type, error) {
 		} else {
 			return nil, errors.Errorf("attempt to set not-null on nullable-incompatible type")
 		}
+
+		// the location of a non-null type includes the "!"
+		if l, ok := typ.(model.Locator); ok {
+			l.SetLocation(pctx.location(start))
+		}
 	}
 	return typ, nil
 }
 
 func (pctx *parseCtx) parseNamedType() (model.NamedType, error) {
+	start := pctx.peek().Pos
 	typname, err := consumeName(pctx)
 	if err != nil {
 		return nil, errors.Wrap(err, `named type`)
 	}
 
 	typ := model.NewNamedType(typname)
+	typ.SetLocation(pctx.location(start))
 	if err := pctx.registerType(typ); err != nil {
 		return nil, errors.Wrap(err, `failed to register type`)
 	}
@@ -503,28 +963,30 @@ func (pctx *parseCtx) parseNamedType() (model.NamedType, error) {
 }
 
 func (pctx *parseCtx) parseListType() (model.ListType, error) {
-	if _, err := consumeToken(pctx, BRACKET_L); err != nil {
-		return nil, errors.Wrap(err, `list type`)
+	if err := pctx.enter(); err != nil {
+		return nil, err
 	}
+	defer pctx.leave()
 
-	typname, err := consumeName(pctx)
-	if err != nil {
+	start := pctx.peek().Pos
+	if _, err := consumeToken(pctx, BRACKET_L); err != nil {
 		return nil, errors.Wrap(err, `list type`)
 	}
 
-	typ, err := pctx.lookupType(typname)
+	// Note: each occurrence gets its own model.NamedType, as they
+	// have different locations (and possibly different nullability)
+	typ, err := pctx.parseType()
 	if err != nil {
-		typ = model.NewNamedType(typname)
-		if err := pctx.registerType(typ); err != nil {
-			return nil, errors.Wrap(err, `failed to register type`)
-		}
+		return nil, errors.Wrap(err, `list type`)
 	}
 
 	if _, err := consumeToken(pctx, BRACKET_R); err != nil {
 		return nil, errors.Wrap(err, `list type`)
 	}
 
-	return model.NewListType(typ), nil
+	list := model.NewListType(typ)
+	list.SetLocation(pctx.location(start))
+	return list, nil
 }
 
 // ValueConst:
@@ -537,41 +999,62 @@ func (pctx *parseCtx) parseListType() (model.ListType, error) {
 //   EnumValue
 //   ListValue [?Const]
 //   ObjectValue [?Const]
-func (pctx *parseCtx) parseValue() (model.Value, error) {
+//
+// Variables are not allowed when isConst is true (e.g. default values)
+func (pctx *parseCtx) parseValue(isConst bool) (model.Value, error) {
+	start := pctx.peek().Pos
+
+	var v model.Value
+	var err error
 	switch t := pctx.peek(); t.Type {
 	case DOLLAR:
+		if isConst {
+			return nil, syntaxErr(t, `variables are not allowed in constant values`)
+		}
 		pctx.advance()
 		name, err := consumeName(pctx)
 		if err != nil {
 			return nil, errors.Wrap(err, `value`)
 		}
-		return model.NewVariable(name), nil
+		v = model.NewVariable(name)
 	case INT:
 		pctx.advance()
-		return model.ParseIntValue(t.Value)
+		v, err = model.ParseIntValue(t.Value)
 	case FLOAT:
 		pctx.advance()
-		return model.NewFloatValue(t.Value)
+		v, err = model.NewFloatValue(t.Value)
 	case STRING:
 		pctx.advance()
-		return model.NewStringValue(t.Value), nil
+		str, err := stringValue(t.Value)
+		if err != nil {
+			return nil, syntaxErr(t, `invalid string: %s`, err)
+		}
+		v = model.NewStringValue(str)
+	case BRACKET_L:
+		v, err = pctx.parseListValue(isConst)
 	case BRACE_L:
-		return pctx.parseObjectValue()
+		v, err = pctx.parseObjectValue(isConst)
 	case NAME:
 		pctx.advance()
 		name := t.Value
 
 		switch name {
 		case trueKey, falseKey:
-			return model.NewBoolValue(name)
+			v, err = model.NewBoolValue(name)
 		case nullKey:
-			return model.NullValue(), nil
+			v = model.NullValue()
 		default:
-			return model.NewEnumValue(name), nil
+			v = model.NewEnumValue(name)
 		}
 	default:
-		return nil, errors.Errorf(`value: unexpected token %s`, t.Type)
+		return nil, unexpectedToken(t, `value`)
 	}
+
+	if err != nil {
+		return nil, err
+	}
+	v.SetLocation(pctx.location(start))
+	return v, nil
 }
 
 func (pctx *parseCtx) parseDirectives() (model.DirectiveList, error) {
@@ -581,7 +1064,7 @@ func (pctx *parseCtx) parseDirectives() (model.DirectiveList, error) {
 			loop = false
 			continue
 		}
-		pctx.advance()
+		start := pctx.next().Pos
 
 		name, err := consumeName(pctx)
 		if err != nil {
@@ -596,6 +1079,7 @@ func (pctx *parseCtx) parseDirectives() (model.DirectiveList, error) {
 			}
 			d.AddArguments(arguments...)
 		}
+		d.SetLocation(pctx.location(start))
 
 		directives.Add(d)
 	}
@@ -605,40 +1089,108 @@ func (pctx *parseCtx) parseDirectives() (model.DirectiveList, error) {
 // SelectionSet:
 //   { Selection... }
 func (pctx *parseCtx) parseSelectionSet() (model.SelectionList, error) {
+	if err := pctx.enter(); err != nil {
+		return nil, err
+	}
+	defer pctx.leave()
+
 	if _, err := consumeToken(pctx, BRACE_L); err != nil {
 		return nil, errors.Wrap(err, `selection set`)
 	}
 
+	braces, parens := pctx.braceDepth, pctx.parenDepth
+
 	var set model.SelectionList
 	for loop := true; loop; {
-		if peekToken(pctx, BRACE_R) {
+		if peekToken(pctx, BRACE_R) || (pctx.errorRecovery && peekToken(pctx, EOF)) {
 			loop = false
 			continue
 		}
 
+		mark := pctx.consumed
 		sel, err := pctx.parseSelection()
 		if err != nil {
-			return nil, errors.Wrap(err, `failed to parse selection`)
+			if !pctx.errorRecovery {
+				return nil, errors.Wrap(err, `failed to parse selection`)
+			}
+			pctx.syntaxError(err)
+			if !pctx.skipSelection(mark, braces, parens) {
+				// the selection set was closed by the broken selection
+				return set, nil
+			}
+			continue
 		}
 		set = append(set, sel)
 	}
 
 	if _, err := consumeToken(pctx, BRACE_R); err != nil {
-		return nil, errors.Wrap(err, `selection set`)
+		if !pctx.errorRecovery {
+			return nil, errors.Wrap(err, `selection set`)
+		}
+		// keep the selections of unterminated selection sets
+		pctx.syntaxError(err)
 	}
 	return set, nil
 }
 
+// skipSelection skips the tokens up to the start of the next selection
+// in the selection set whose contents are at the nesting depth given
+// by `braces` and `parens`, or up to the end of that selection set,
+// after a syntax error has been found in a selection that started when
+// `mark` tokens had been consumed. Returns false if the selection set
+// has already been closed
+func (pctx *parseCtx) skipSelection(mark, braces, parens int) bool {
+	if pctx.consumed == mark {
+		pctx.advance()
+	}
+
+	for {
+		if pctx.braceDepth < braces {
+			return false
+		}
+
+		switch t := pctx.peek(); t.Type {
+		case EOF:
+			return true
+		case BRACE_R:
+			if pctx.braceDepth == braces {
+				pctx.parenDepth = parens
+				return true
+			}
+		case NAME, SPREAD:
+			if pctx.braceDepth == braces && pctx.parenDepth == parens {
+				return true
+			}
+		}
+		pctx.advance()
+	}
+}
+
 // Selection:
 //   Field
 //   FragmentSpread
 //   InlineFragment
 func (pctx *parseCtx) parseSelection() (model.Selection, error) {
+	start := pctx.peek().Pos
+
+	var sel model.Selection
+	var err error
 	if peekToken(pctx, SPREAD) {
 		pctx.advance()
-		return pctx.parseFragmentSpreadOrInlineFragment()
+		sel, err = pctx.parseFragmentSpreadOrInlineFragment()
+	} else {
+		sel, err = pctx.parseSelectionField()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: the location of fragment spreads and inline fragments
+	// includes the leading "..."
+	if l, ok := sel.(model.Locator); ok {
+		l.SetLocation(pctx.location(start))
 	}
-	return pctx.parseSelectionField()
+	return sel, nil
 }
 
 func (pctx *parseCtx) parseSelectionField() (model.SelectionField, error) {
@@ -709,7 +1261,7 @@ func (pctx *parseCtx) parseFragmentSpreadOrInlineFragment() (model.Selection, er
 		// it's something else, then
 		return pctx.parseFragmentSpread()
 	default:
-		return nil, errors.Errorf(`expected FragmentSpread or InlineFragment`)
+		return nil, unexpectedToken(t, `fragment spread or inline fragment`, NAME, BRACE_L, AT)
 	}
 }
 
@@ -726,6 +1278,7 @@ func (pctx *parseCtx) parseArguments() (model.ArgumentList, error) {
 			continue
 		}
 
+		start := pctx.peek().Pos
 		name, err := consumeName(pctx)
 		if err != nil {
 			return nil, errors.Wrap(err, `arguments`)
@@ -735,12 +1288,14 @@ func (pctx *parseCtx) parseArguments() (model.ArgumentList, error) {
 			return nil, errors.Wrap(err, `arguments`)
 		}
 
-		value, err := pctx.parseValue()
+		value, err := pctx.parseValue(false)
 		if err != nil {
 			return nil, errors.Wrap(err, `failed to parse value`)
 		}
 
-		args = append(args, model.NewArgument(name, value))
+		arg := model.NewArgument(name, value)
+		arg.SetLocation(pctx.location(start))
+		args = append(args, arg)
 
 	}
 
@@ -808,11 +1363,49 @@ func (pctx *parseCtx) parseInlineFragment() (model.InlineFragment, error) {
 	return frag, nil
 }
 
+// ListValue:
+//   [ Value... ]
+func (pctx *parseCtx) parseListValue(isConst bool) (model.ListValue, error) {
+	if err := pctx.enter(); err != nil {
+		return nil, err
+	}
+	defer pctx.leave()
+
+	if _, err := consumeToken(pctx, BRACKET_L); err != nil {
+		return nil, errors.Wrap(err, `list value`)
+	}
+
+	list := model.NewListValue()
+	for loop := true; loop; {
+		if peekToken(pctx, BRACKET_R) {
+			loop = false
+			continue
+		}
+
+		v, err := pctx.parseValue(isConst)
+		if err != nil {
+			return nil, errors.Wrap(err, `list value: failed to parse value`)
+		}
+
+		list.AddValues(v)
+	}
+
+	if _, err := consumeToken(pctx, BRACKET_R); err != nil {
+		return nil, errors.Wrap(err, `list value`)
+	}
+	return list, nil
+}
+
 // ObjectValue:
 //   { ObjectField? }
 // ObjectField:
 //   Name : Value
-func (pctx *parseCtx) parseObjectValue() (model.ObjectValue, error) {
+func (pctx *parseCtx) parseObjectValue(isConst bool) (model.ObjectValue, error) {
+	if err := pctx.enter(); err != nil {
+		return nil, err
+	}
+	defer pctx.leave()
+
 	if _, err := consumeToken(pctx, BRACE_L); err != nil {
 		return nil, errors.Wrap(err, `object value`)
 	}
@@ -824,7 +1417,7 @@ func (pctx *parseCtx) parseObjectValue() (model.ObjectValue, error) {
 			continue
 		}
 
-		field, err := pctx.parseObjectField()
+		field, err := pctx.parseObjectField(isConst)
 		if err != nil {
 			return nil, errors.Wrap(err, `failed to parse object field`)
 		}
@@ -838,7 +1431,8 @@ func (pctx *parseCtx) parseObjectValue() (model.ObjectValue, error) {
 	return obj, nil
 }
 
-func (pctx *parseCtx) parseObjectField() (model.ObjectField, error) {
+func (pctx *parseCtx) parseObjectField(isConst bool) (model.ObjectField, error) {
+	start := pctx.peek().Pos
 	name, err := consumeName(pctx)
 	if err != nil {
 		return nil, errors.Wrap(err, `object field`)
@@ -848,14 +1442,18 @@ func (pctx *parseCtx) parseObjectField() (model.ObjectField, error) {
 		return nil, errors.Wrap(err, `object field`)
 	}
 
-	v, err := pctx.parseValue()
+	v, err := pctx.parseValue(isConst)
 	if err != nil {
 		return nil, errors.Wrap(err, `object field: failed to parse value`)
 	}
-	return model.NewObjectField(name, v), nil
+
+	field := model.NewObjectField(name, v)
+	field.SetLocation(pctx.location(start))
+	return field, nil
 }
 
 func (pctx *parseCtx) parseObjectDefinition() (model.ObjectDefinition, error) {
+	start := pctx.peek().Pos
 	if _, err := consumeName(pctx, typeKey); err != nil {
 		return nil, errors.Wrap(err, `object type`)
 	}
@@ -865,20 +1463,90 @@ func (pctx *parseCtx) parseObjectDefinition() (model.ObjectDefinition, error) {
 		return nil, errors.Wrap(err, `object type`)
 	}
 
-	var implType model.NamedType
-	if peekName(pctx, implementsKey) {
-		if _, err := consumeName(pctx, implementsKey); err != nil {
-			return nil, errors.Wrap(err, `object type`)
+	interfaces, err := pctx.parseImplementsInterfaces()
+	if err != nil {
+		return nil, errors.Wrap(err, `object type`)
+	}
+
+	var directives model.DirectiveList
+	if peekToken(pctx, AT) {
+		var err error
+		directives, err = pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
 		}
+	}
+
+	fields, err := pctx.parseObjectFieldDefinitions()
+	if err != nil {
+		return nil, errors.Wrap(err, `object type`)
+	}
+
+	def := model.NewObjectDefinition(name)
+	def.AddDirectives(directives...)
+	def.AddInterfaces(interfaces...)
+	def.AddFields(fields...)
+	def.SetLocation(pctx.location(start))
+	return def, nil
+}
+
+// ImplementsInterfaces:
+//   implements &? NamedType (& NamedType)...
+// The legacy form, where the names are separated by spaces, is also
+// accepted. Returns an empty list if there is no implements clause
+func (pctx *parseCtx) parseImplementsInterfaces() (model.NamedTypeList, error) {
+	if !peekName(pctx, implementsKey) {
+		return nil, nil
+	}
+	pctx.advance()
+
+	var ampersand bool
+	if peekToken(pctx, AMP) {
+		pctx.advance()
+		ampersand = true
+	}
 
-		implType, err = pctx.parseNamedType()
+	var interfaces model.NamedTypeList
+	for {
+		typ, err := pctx.parseNamedType()
 		if err != nil {
-			return nil, errors.Wrap(err, `object type`)
+			return nil, errors.Wrap(err, `implements`)
+		}
+		interfaces.Add(typ)
+
+		if peekToken(pctx, AMP) {
+			if !ampersand && len(interfaces) > 1 {
+				return nil, syntaxErr(pctx.peek(), `implements: & can not be mixed with space separated names`)
+			}
+			pctx.advance()
+			ampersand = true
+			continue
+		}
+
+		// the legacy form ends at the keywords that start the next
+		// definition, which may follow a type extension
+		if t := pctx.peek(); !ampersand && t.Type == NAME && !isDefinitionKey(t.Value) {
+			continue
 		}
+		return interfaces, nil
+	}
+}
+
+// isDefinitionKey returns true if `name` is one of the keywords that
+// start a definition in a document
+func isDefinitionKey(name string) bool {
+	switch name {
+	case queryKey, mutationKey, subscriptionKey, fragmentKey, typeKey, enumKey, interfaceKey, unionKey, inputKey, scalarKey, directiveKey, schemaKey, extendKey:
+		return true
 	}
+	return false
+}
 
+// parseObjectFieldDefinitions parses the fields of an object type,
+// including the surrounding braces
+func (pctx *parseCtx) parseObjectFieldDefinitions() (model.ObjectFieldDefinitionList, error) {
 	if _, err := consumeToken(pctx, BRACE_L); err != nil {
-		return nil, errors.Wrap(err, `object type`)
+		return nil, err
 	}
 
 	var fields model.ObjectFieldDefinitionList
@@ -896,18 +1564,18 @@ func (pctx *parseCtx) parseObjectDefinition() (model.ObjectDefinition, error) {
 	}
 
 	if _, err := consumeToken(pctx, BRACE_R); err != nil {
-		return nil, errors.Wrap(err, `object type`)
+		return nil, err
 	}
-
-	def := model.NewObjectDefinition(name)
-	def.AddFields(fields...)
-	if implType != nil {
-		def.SetImplements(implType)
-	}
-	return def, nil
+	return fields, nil
 }
 
 func (pctx *parseCtx) parseObjectFieldDefinition() (model.ObjectFieldDefinition, error) {
+	start := pctx.peek().Pos
+	description, err := pctx.parseDescription()
+	if err != nil {
+		return nil, errors.Wrap(err, `object field`)
+	}
+
 	name, err := consumeName(pctx)
 	if err != nil {
 		return nil, errors.Wrap(err, `object field`)
@@ -931,7 +1599,16 @@ func (pctx *parseCtx) parseObjectFieldDefinition() (model.ObjectFieldDefinition,
 		return nil, errors.Wrap(err, `object field: failed to parse type`)
 	}
 	f := model.NewObjectFieldDefinition(name, typ)
+	f.SetDescription(description)
+	if peekToken(pctx, AT) {
+		directives, err := pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+		f.AddDirectives(directives...)
+	}
 	f.AddArguments(arguments...)
+	f.SetLocation(pctx.location(start))
 	return f, nil
 }
 
@@ -948,6 +1625,12 @@ func (pctx *parseCtx) parseObjectFieldArgumentDefinitions() (model.ObjectFieldAr
 			continue
 		}
 
+		start := pctx.peek().Pos
+		description, err := pctx.parseDescription()
+		if err != nil {
+			return nil, errors.Wrap(err, `object field arguments`)
+		}
+
 		name, err := consumeName(pctx)
 		if err != nil {
 			return nil, errors.Wrap(err, `object field arguments`)
@@ -963,17 +1646,27 @@ func (pctx *parseCtx) parseObjectFieldArgumentDefinitions() (model.ObjectFieldAr
 		}
 
 		arg := model.NewObjectFieldArgumentDefinition(name, typ)
+		arg.SetDescription(description)
 
 		if peekToken(pctx, EQUALS) {
 			// we have default
 			pctx.advance()
-			value, err := pctx.parseValue()
+			value, err := pctx.parseValue(true)
 			if err != nil {
 				return nil, errors.Wrap(err, `failed to parse object field default value`)
 			}
 			arg.SetDefaultValue(value)
 		}
 
+		if peekToken(pctx, AT) {
+			directives, err := pctx.parseDirectives()
+			if err != nil {
+				return nil, errors.Wrap(err, `failed to parse directives`)
+			}
+			arg.AddDirectives(directives...)
+		}
+		arg.SetLocation(pctx.location(start))
+
 		args = append(args, arg)
 	}
 
@@ -985,6 +1678,7 @@ func (pctx *parseCtx) parseObjectFieldArgumentDefinitions() (model.ObjectFieldAr
 }
 
 func (pctx *parseCtx) parseEnumDefinition() (model.EnumDefinition, error) {
+	start := pctx.peek().Pos
 	if _, err := consumeName(pctx, enumKey); err != nil {
 		return nil, errors.Wrap(err, `enum`)
 	}
@@ -994,10 +1688,34 @@ func (pctx *parseCtx) parseEnumDefinition() (model.EnumDefinition, error) {
 		return nil, errors.Wrap(err, `enum`)
 	}
 
-	if _, err := consumeToken(pctx, BRACE_L); err != nil {
+	var directives model.DirectiveList
+	if peekToken(pctx, AT) {
+		var err error
+		directives, err = pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+	}
+
+	elements, err := pctx.parseEnumElementDefinitions()
+	if err != nil {
 		return nil, errors.Wrap(err, `enum`)
 	}
 
+	def := model.NewEnumDefinition(name)
+	def.AddDirectives(directives...)
+	def.AddElements(elements...)
+	def.SetLocation(pctx.location(start))
+	return def, nil
+}
+
+// parseEnumElementDefinitions parses the values of an enum type,
+// including the surrounding braces. Values are numbered from 1
+func (pctx *parseCtx) parseEnumElementDefinitions() (model.EnumElementDefinitionList, error) {
+	if _, err := consumeToken(pctx, BRACE_L); err != nil {
+		return nil, err
+	}
+
 	var elements model.EnumElementDefinitionList
 	var val = 1
 	for loop := true; loop; {
@@ -1006,24 +1724,38 @@ func (pctx *parseCtx) parseEnumDefinition() (model.EnumDefinition, error) {
 			continue
 		}
 
+		elemStart := pctx.peek().Pos
+		description, err := pctx.parseDescription()
+		if err != nil {
+			return nil, err
+		}
+
 		elem, err := consumeName(pctx)
 		if err != nil {
-			return nil, errors.Wrap(err, `enum`)
+			return nil, err
+		}
+		e := model.NewEnumElementDefinition(elem, model.NewIntValue(val))
+		e.SetDescription(description)
+		if peekToken(pctx, AT) {
+			directives, err := pctx.parseDirectives()
+			if err != nil {
+				return nil, errors.Wrap(err, `failed to parse directives`)
+			}
+			e.AddDirectives(directives...)
 		}
-		elements.Add(model.NewEnumElementDefinition(elem, model.NewIntValue(val)))
+		e.SetLocation(pctx.location(elemStart))
+		elements.Add(e)
 		val++
 	}
 
 	if _, err := consumeToken(pctx, BRACE_R); err != nil {
-		return nil, errors.Wrap(err, `enum`)
+		return nil, err
 	}
-
-	def := model.NewEnumDefinition(name)
-	def.AddElements(elements...)
-	return def, nil
+	return elements, nil
 }
 
 func (pctx *parseCtx) parseInterfaceDefinition() (model.InterfaceDefinition, error) {
+	start := pctx.peek().Pos
 	if _, err := consumeName(pctx, interfaceKey); err != nil {
 		return nil, errors.Wrap(err, `interface`)
 	}
@@ -1033,10 +1765,40 @@ func (pctx *parseCtx) parseInterfaceDefinition() (model.InterfaceDefinition, err
 		return nil, errors.Wrap(err, `interface`)
 	}
 
-	if _, err := consumeToken(pctx, BRACE_L); err != nil {
+	interfaces, err := pctx.parseImplementsInterfaces()
+	if err != nil {
+		return nil, errors.Wrap(err, `interface`)
+	}
+
+	var directives model.DirectiveList
+	if peekToken(pctx, AT) {
+		var err error
+		directives, err = pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+	}
+
+	fields, err := pctx.parseInterfaceFieldDefinitions()
+	if err != nil {
 		return nil, errors.Wrap(err, `interface`)
 	}
 
+	iface := model.NewInterfaceDefinition(name)
+	iface.AddDirectives(directives...)
+	iface.AddInterfaces(interfaces...)
+	iface.AddFields(fields...)
+	iface.SetLocation(pctx.location(start))
+	return iface, nil
+}
+
+// parseInterfaceFieldDefinitions parses the fields of an interface,
+// including the surrounding braces
+func (pctx *parseCtx) parseInterfaceFieldDefinitions() (model.InterfaceFieldDefinitionList, error) {
+	if _, err := consumeToken(pctx, BRACE_L); err != nil {
+		return nil, err
+	}
+
 	var fields model.InterfaceFieldDefinitionList
 	for loop := true; loop; {
 		if peekToken(pctx, BRACE_R) {
@@ -1046,25 +1808,38 @@ func (pctx *parseCtx) parseInterfaceDefinition() (model.InterfaceDefinition, err
 
 		field, err := pctx.parseInterfaceDefinitionField()
 		if err != nil {
-			return nil, errors.Wrap(err, `interface`)
+			return nil, err
 		}
 		fields.Add(field)
 	}
 
 	if _, err := consumeToken(pctx, BRACE_R); err != nil {
-		return nil, errors.Wrap(err, `interface`)
+		return nil, err
 	}
-	iface := model.NewInterfaceDefinition(name)
-	iface.AddFields(fields...)
-	return iface, nil
+	return fields, nil
 }
 
 func (pctx *parseCtx) parseInterfaceDefinitionField() (model.InterfaceFieldDefinition, error) {
+	start := pctx.peek().Pos
+	description, err := pctx.parseDescription()
+	if err != nil {
+		return nil, errors.Wrap(err, `interface field`)
+	}
+
 	name, err := consumeName(pctx)
 	if err != nil {
 		return nil, errors.Wrap(err, `interface field`)
 	}
 
+	var arguments model.ObjectFieldArgumentDefinitionList
+	if peekToken(pctx, PAREN_L) {
+		var err error
+		arguments, err = pctx.parseObjectFieldArgumentDefinitions()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse arguments`)
+		}
+	}
+
 	if _, err := consumeToken(pctx, COLON); err != nil {
 		return nil, errors.Wrap(err, `interface field`)
 	}
@@ -1074,10 +1849,22 @@ func (pctx *parseCtx) parseInterfaceDefinitionField() (model.InterfaceFieldDefin
 		return nil, errors.Wrap(err, `interface field`)
 	}
 
-	return model.NewInterfaceFieldDefinition(name, typ), nil
+	f := model.NewInterfaceFieldDefinition(name, typ)
+	f.SetDescription(description)
+	if peekToken(pctx, AT) {
+		directives, err := pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+		f.AddDirectives(directives...)
+	}
+	f.AddArguments(arguments...)
+	f.SetLocation(pctx.location(start))
+	return f, nil
 }
 
 func (pctx *parseCtx) parseUnionDefinition() (model.UnionDefinition, error) {
+	start := pctx.peek().Pos
 	if _, err := consumeName(pctx, unionKey); err != nil {
 		return nil, errors.Wrap(err, `union`)
 	}
@@ -1087,15 +1874,35 @@ func (pctx *parseCtx) parseUnionDefinition() (model.UnionDefinition, error) {
 		return nil, errors.Wrap(err, `union`)
 	}
 
-	if _, err := consumeToken(pctx, EQUALS); err != nil {
+	union := model.NewUnionDefinition(name)
+	if peekToken(pctx, AT) {
+		directives, err := pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+		union.AddDirectives(directives...)
+	}
+
+	types, err := pctx.parseUnionMemberTypes()
+	if err != nil {
 		return nil, errors.Wrap(err, `union`)
 	}
+	union.AddTypes(types...)
+	union.SetLocation(pctx.location(start))
 
-	union := model.NewUnionDefinition(name)
+	return union, nil
+}
+
+// parseUnionMemberTypes parses the member types of a union, including
+// the leading =
+func (pctx *parseCtx) parseUnionMemberTypes() (model.TypeList, error) {
+	if _, err := consumeToken(pctx, EQUALS); err != nil {
+		return nil, err
+	}
 
 	typ, err := pctx.parseType()
 	if err != nil {
-		return nil, errors.Wrap(err, `union`)
+		return nil, err
 	}
 
 	var types model.TypeList
@@ -1110,16 +1917,15 @@ func (pctx *parseCtx) parseUnionDefinition() (model.UnionDefinition, error) {
 
 		typ, err := pctx.parseType()
 		if err != nil {
-			return nil, errors.Wrap(err, `union`)
+			return nil, err
 		}
 		types.Add(typ)
 	}
-	union.AddTypes(types...)
-
-	return union, nil
+	return types, nil
 }
 
 func (pctx *parseCtx) parseInputDefinition() (model.InputDefinition, error) {
+	start := pctx.peek().Pos
 	if _, err := consumeName(pctx, inputKey); err != nil {
 		return nil, errors.Wrap(err, `input`)
 	}
@@ -1129,10 +1935,34 @@ func (pctx *parseCtx) parseInputDefinition() (model.InputDefinition, error) {
 		return nil, errors.Wrap(err, `input`)
 	}
 
-	if _, err := consumeToken(pctx, BRACE_L); err != nil {
+	var directives model.DirectiveList
+	if peekToken(pctx, AT) {
+		var err error
+		directives, err = pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+	}
+
+	fields, err := pctx.parseInputFieldDefinitions()
+	if err != nil {
 		return nil, errors.Wrap(err, `input`)
 	}
 
+	iface := model.NewInputDefinition(name)
+	iface.AddDirectives(directives...)
+	iface.AddFields(fields...)
+	iface.SetLocation(pctx.location(start))
+	return iface, nil
+}
+
+// parseInputFieldDefinitions parses the fields of an input type,
+// including the surrounding braces
+func (pctx *parseCtx) parseInputFieldDefinitions() (model.InputFieldDefinitionList, error) {
+	if _, err := consumeToken(pctx, BRACE_L); err != nil {
+		return nil, err
+	}
+
 	var fields model.InputFieldDefinitionList
 	for loop := true; loop; {
 		if peekToken(pctx, BRACE_R) {
@@ -1142,20 +1972,24 @@ func (pctx *parseCtx) parseInputDefinition() (model.InputDefinition, error) {
 
 		field, err := pctx.parseInputDefinitionField()
 		if err != nil {
-			return nil, errors.Wrap(err, `input`)
+			return nil, err
 		}
 		fields.Add(field)
 	}
 
 	if _, err := consumeToken(pctx, BRACE_R); err != nil {
-		return nil, errors.Wrap(err, `input`)
+		return nil, err
 	}
-	iface := model.NewInputDefinition(name)
-	iface.AddFields(fields...)
-	return iface, nil
+	return fields, nil
 }
 
 func (pctx *parseCtx) parseInputDefinitionField() (model.InputFieldDefinition, error) {
+	start := pctx.peek().Pos
+	description, err := pctx.parseDescription()
+	if err != nil {
+		return nil, errors.Wrap(err, `input field`)
+	}
+
 	name, err := consumeName(pctx)
 	if err != nil {
 		return nil, errors.Wrap(err, `input field`)
@@ -1172,19 +2006,148 @@ func (pctx *parseCtx) parseInputDefinitionField() (model.InputFieldDefinition, e
 
 	def := model.NewInputFieldDefinition(name)
 	def.SetType(typ)
+	def.SetDescription(description)
+	if peekToken(pctx, EQUALS) {
+		pctx.advance()
+		value, err := pctx.parseValue(true)
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse input field default value`)
+		}
+		def.SetDefaultValue(value)
+	}
+
+	if peekToken(pctx, AT) {
+		directives, err := pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+		def.AddDirectives(directives...)
+	}
+	def.SetLocation(pctx.location(start))
+	return def, nil
+}
+
+// ScalarTypeDefinition:
+//   scalar Name Directives?
+func (pctx *parseCtx) parseScalarDefinition() (model.ScalarDefinition, error) {
+	start := pctx.peek().Pos
+	if _, err := consumeName(pctx, scalarKey); err != nil {
+		return nil, errors.Wrap(err, `scalar`)
+	}
+
+	name, err := consumeName(pctx)
+	if err != nil {
+		return nil, errors.Wrap(err, `scalar`)
+	}
+
+	def := model.NewScalarDefinition(name)
+	if peekToken(pctx, AT) {
+		directives, err := pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+		def.AddDirectives(directives...)
+	}
+	def.SetLocation(pctx.location(start))
+	return def, nil
+}
+
+// DirectiveDefinition:
+//   directive @ Name ArgumentsDefinition? repeatable? on DirectiveLocations
+// DirectiveLocations:
+//   |? DirectiveLocation (| DirectiveLocation)...
+func (pctx *parseCtx) parseDirectiveDefinition() (model.DirectiveDefinition, error) {
+	start := pctx.peek().Pos
+	if _, err := consumeName(pctx, directiveKey); err != nil {
+		return nil, errors.Wrap(err, `directive`)
+	}
+
+	if _, err := consumeToken(pctx, AT); err != nil {
+		return nil, errors.Wrap(err, `directive`)
+	}
+
+	name, err := consumeName(pctx)
+	if err != nil {
+		return nil, errors.Wrap(err, `directive`)
+	}
+
+	def := model.NewDirectiveDefinition(name)
+	if peekToken(pctx, PAREN_L) {
+		arguments, err := pctx.parseObjectFieldArgumentDefinitions()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse arguments`)
+		}
+		def.AddArguments(arguments...)
+	}
+
+	if peekName(pctx, repeatableKey) {
+		pctx.advance()
+		def.SetRepeatable(true)
+	}
+
+	if _, err := consumeName(pctx, onKey); err != nil {
+		return nil, errors.Wrap(err, `directive`)
+	}
+
+	if peekToken(pctx, PIPE) {
+		pctx.advance()
+	}
+
+	for {
+		t, err := consumeToken(pctx, NAME)
+		if err != nil {
+			return nil, errors.Wrap(err, `directive location`)
+		}
+
+		location := model.DirectiveLocation(t.Value)
+		if !location.IsValid() {
+			return nil, syntaxErr(t, `invalid directive location %s`, t.Value)
+		}
+		def.AddLocations(location)
+
+		if !peekToken(pctx, PIPE) {
+			break
+		}
+		pctx.advance()
+	}
+
+	def.SetLocation(pctx.location(start))
 	return def, nil
 }
 
+// SchemaDefinition:
+//   schema Directives? { RootOperationTypeDefinition... }
+// RootOperationTypeDefinition:
+//   OperationType : NamedType
 func (pctx *parseCtx) parseSchemaDefinition() (model.Schema, error) {
+	start := pctx.peek().Pos
 	if _, err := consumeName(pctx, schemaKey); err != nil {
 		return nil, errors.Wrap(err, `schema`)
 	}
 
+	var directives model.DirectiveList
+	if peekToken(pctx, AT) {
+		var err error
+		directives, err = pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+	}
+
 	if _, err := consumeToken(pctx, BRACE_L); err != nil {
 		return nil, errors.Wrap(err, `schema`)
 	}
 
-	var query model.NamedType
+	if peekToken(pctx, BRACE_R) {
+		return nil, syntaxErr(pctx.peek(), `expected at least one root operation type`)
+	}
+
+	keys := []string{queryKey, mutationKey, subscriptionKey}
+	if pctx.schemaTypes {
+		keys = append(keys, typesKey)
+	}
+
+	var query, mutation, subscription model.NamedType
 	var types model.NamedTypeList
 	for loop := true; loop; {
 		if peekToken(pctx, BRACE_R) {
@@ -1192,15 +2155,25 @@ func (pctx *parseCtx) parseSchemaDefinition() (model.Schema, error) {
 			continue
 		}
 
-		name, err := consumeName(pctx, queryKey, typesKey)
+		tok := *pctx.peek()
+		name, err := consumeName(pctx, keys...)
 		if err != nil {
 			return nil, errors.Wrap(err, `schema`)
 		}
 
 		switch name {
-		case queryKey:
-			if query != nil {
-				return nil, errors.New(`duplicate query key in schema`)
+		case queryKey, mutationKey, subscriptionKey:
+			var dst *model.NamedType
+			switch name {
+			case queryKey:
+				dst = &query
+			case mutationKey:
+				dst = &mutation
+			case subscriptionKey:
+				dst = &subscription
+			}
+			if *dst != nil {
+				return nil, syntaxErr(&tok, `duplicate %s key in schema`, name)
 			}
 
 			if _, err := consumeToken(pctx, COLON); err != nil {
@@ -1211,10 +2184,10 @@ func (pctx *parseCtx) parseSchemaDefinition() (model.Schema, error) {
 			if err != nil {
 				return nil, errors.Wrap(err, `schema`)
 			}
-			query = typ
+			*dst = typ
 		case typesKey:
 			if types != nil {
-				return nil, errors.New(`duplicate types key in schema`)
+				return nil, syntaxErr(&tok, `duplicate types key in schema`)
 			}
 
 			if _, err := consumeToken(pctx, COLON); err != nil {
@@ -1249,6 +2222,206 @@ func (pctx *parseCtx) parseSchemaDefinition() (model.Schema, error) {
 	}
 	s := model.NewSchema()
 	s.SetQuery(query)
+	s.SetMutation(mutation)
+	s.SetSubscription(subscription)
+	s.AddDirectives(directives...)
 	s.AddTypes(types...)
+	s.SetLocation(pctx.location(start))
 	return s, nil
 }
+
+// TypeSystemExtension:
+//   extend schema Directives? { OperationTypeDefinition... }
+//   extend scalar Name Directives
+//   extend type Name ImplementsInterfaces? Directives? FieldsDefinition?
+//   extend interface Name Directives? FieldsDefinition?
+//   extend union Name Directives? UnionMemberTypes?
+//   extend enum Name Directives? EnumValuesDefinition?
+//   extend input Name Directives? InputFieldsDefinition?
+// An extension must add at least one of the optional parts
+func (pctx *parseCtx) parseTypeExtension() (model.Extension, error) {
+	start := pctx.peek().Pos
+	if _, err := consumeName(pctx, extendKey); err != nil {
+		return nil, errors.Wrap(err, `extend`)
+	}
+
+	t := *pctx.peek()
+	if t.Type != NAME {
+		return nil, unexpectedToken(&t, `type extension`, NAME)
+	}
+	kind := t.Value
+	pctx.advance()
+
+	if kind == schemaKey {
+		ext, err := pctx.parseSchemaExtension(t)
+		if err != nil {
+			return nil, errors.Wrap(err, `extend schema`)
+		}
+		ext.SetLocation(pctx.location(start))
+		return ext, nil
+	}
+
+	switch kind {
+	case typeKey, interfaceKey, unionKey, enumKey, inputKey, scalarKey:
+	default:
+		return nil, unexpectedName(&t, `type extension`, schemaKey, scalarKey, typeKey, interfaceKey, unionKey, enumKey, inputKey)
+	}
+
+	name, err := consumeName(pctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, `extend %s`, kind)
+	}
+
+	var interfaces model.NamedTypeList
+	if kind == typeKey || kind == interfaceKey {
+		interfaces, err = pctx.parseImplementsInterfaces()
+		if err != nil {
+			return nil, errors.Wrapf(err, `extend %s`, kind)
+		}
+	}
+
+	var directives model.DirectiveList
+	if peekToken(pctx, AT) {
+		directives, err = pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+	}
+
+	var ext model.Extension
+	var empty = len(interfaces) == 0 && len(directives) == 0
+	switch kind {
+	case typeKey:
+		def := model.NewObjectExtension(name)
+		def.AddInterfaces(interfaces...)
+		if peekToken(pctx, BRACE_L) {
+			fields, err := pctx.parseObjectFieldDefinitions()
+			if err != nil {
+				return nil, errors.Wrapf(err, `extend %s`, kind)
+			}
+			def.AddFields(fields...)
+			empty = false
+		}
+		ext = def
+	case interfaceKey:
+		def := model.NewInterfaceExtension(name)
+		def.AddInterfaces(interfaces...)
+		if peekToken(pctx, BRACE_L) {
+			fields, err := pctx.parseInterfaceFieldDefinitions()
+			if err != nil {
+				return nil, errors.Wrapf(err, `extend %s`, kind)
+			}
+			def.AddFields(fields...)
+			empty = false
+		}
+		ext = def
+	case unionKey:
+		def := model.NewUnionExtension(name)
+		if peekToken(pctx, EQUALS) {
+			types, err := pctx.parseUnionMemberTypes()
+			if err != nil {
+				return nil, errors.Wrapf(err, `extend %s`, kind)
+			}
+			def.AddTypes(types...)
+			empty = false
+		}
+		ext = def
+	case enumKey:
+		def := model.NewEnumExtension(name)
+		if peekToken(pctx, BRACE_L) {
+			elements, err := pctx.parseEnumElementDefinitions()
+			if err != nil {
+				return nil, errors.Wrapf(err, `extend %s`, kind)
+			}
+			def.AddElements(elements...)
+			empty = false
+		}
+		ext = def
+	case inputKey:
+		def := model.NewInputExtension(name)
+		if peekToken(pctx, BRACE_L) {
+			fields, err := pctx.parseInputFieldDefinitions()
+			if err != nil {
+				return nil, errors.Wrapf(err, `extend %s`, kind)
+			}
+			def.AddFields(fields...)
+			empty = false
+		}
+		ext = def
+	case scalarKey:
+		ext = model.NewScalarExtension(name)
+	}
+
+	if empty {
+		return nil, syntaxErr(&t, `extend %s %s: extension does not add anything`, kind, name)
+	}
+
+	ext.AddDirectives(directives...)
+	ext.SetLocation(pctx.location(start))
+	return ext, nil
+}
+
+func (pctx *parseCtx) parseSchemaExtension(t Token) (model.SchemaExtension, error) {
+	ext := model.NewSchemaExtension()
+	if peekToken(pctx, AT) {
+		directives, err := pctx.parseDirectives()
+		if err != nil {
+			return nil, errors.Wrap(err, `failed to parse directives`)
+		}
+		ext.AddDirectives(directives...)
+	}
+
+	if !peekToken(pctx, BRACE_L) {
+		if len(ext.Directives()) == 0 {
+			return nil, syntaxErr(&t, `extension does not add anything`)
+		}
+		return ext, nil
+	}
+	pctx.advance()
+
+	if peekToken(pctx, BRACE_R) {
+		return nil, syntaxErr(pctx.peek(), `expected at least one root operation type`)
+	}
+
+	for loop := true; loop; {
+		if peekToken(pctx, BRACE_R) {
+			loop = false
+			continue
+		}
+
+		tok := *pctx.peek()
+		name, err := consumeName(pctx, queryKey, mutationKey, subscriptionKey)
+		if err != nil {
+			return nil, err
+		}
+
+		var get func() model.NamedType
+		var set func(model.NamedType)
+		switch name {
+		case queryKey:
+			get, set = ext.Query, ext.SetQuery
+		case mutationKey:
+			get, set = ext.Mutation, ext.SetMutation
+		case subscriptionKey:
+			get, set = ext.Subscription, ext.SetSubscription
+		}
+		if get() != nil {
+			return nil, syntaxErr(&tok, `duplicate %s key in schema`, name)
+		}
+
+		if _, err := consumeToken(pctx, COLON); err != nil {
+			return nil, err
+		}
+
+		typ, err := pctx.parseNamedType()
+		if err != nil {
+			return nil, err
+		}
+		set(typ)
+	}
+
+	if _, err := consumeToken(pctx, BRACE_R); err != nil {
+		return nil, err
+	}
+	return ext, nil
+}
@@ -5,10 +5,13 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/lestrrat/go-graphql/format"
+	"github.com/lestrrat/go-graphql/model"
 	"github.com/lestrrat/go-graphql/parser"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -232,7 +235,1239 @@ mutation CreateReviewForEpisode($ep: Episode!, $review: ReviewInput!) {
 	t.Run(parseSuccess(`schema {
   query: Foo
 }`))
-//  types: [Bar, Baz, Quux] (TODO from above test)
+	t.Run(parseSuccess(`schema {
+  query: Query
+  mutation: Mutation
+  subscription: Subscription
+}`))
+	t.Run(parseSuccess(`"The Star Wars schema"
+schema {
+  query: Query
+}`))
+	t.Run(parseSuccess(`subscription OnReview($ep: Episode) {
+  reviewAdded(episode: $ep) {
+    stars
+  }
+}`))
+	t.Run(parseSuccess(`query HeroesById($ids: [ID!]! = ["1000", "1001"]) {
+  heroes(ids: $ids, episodes: [NEWHOPE, JEDI], matrix: [[1, 2], [], [$x]]) {
+    name
+  }
+}`))
+	t.Run(parseSuccess(`type Query {
+  matrix(rows: [[Int!]!] = [[1]]): [[Int]]
+}`))
+	t.Run(parseSuccess(`{
+  search(text: "Say \"Hello\"\\n\t\u0001", block: """
+  First line
+    Indented line
+
+  Last line with \"""
+  """) {
+    id
+  }
+}`))
+	t.Run(parseSuccess(`schema @link(url: "https://example.com/schema") @frozen {
+  query: Query
+  subscription: Subscription
+}`))
+	t.Run(parseSuccess(`"""
+A character
+in the Star Wars Trilogy
+"""
+interface Character {
+  "The id of the character."
+  id: String!
+}
+
+"One of the films"
+enum Episode {
+  "Released in 1977."
+  NEWHOPE
+  JEDI
+}
 
+"Anything that can be searched"
+union SearchResult = Human | Droid
+
+"A review"
+input ReviewInput {
+  "The number of stars"
+  stars: Int!
+  commentary: String
+}
+
+type Query {
+  "Look up a hero"
+  hero(
+    "The film"
+    episode: Episode
+    first: Int = 10
+  ): Character
+}`))
+	t.Run(parseSuccess(`scalar DateTime @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+"Arbitrary JSON"
+scalar JSON
+
+type Query {
+  now: DateTime
+}`))
+	t.Run(parseSuccess(`"Requires the given role"
+directive @auth(role: Role!, message: String = "denied") repeatable on FIELD_DEFINITION | OBJECT
+
+directive @cached on QUERY`))
+	t.Run(parseSuccess(`type User implements Node @key(fields: "id") @shareable {
+  id: ID!
+  name(format: String = "full" @deprecated): String
+  oldField: String @deprecated(reason: "x")
+}
+
+interface Node @tag(name: "node") {
+  id: ID! @external
+}
+
+enum Role @tag(name: "role") {
+  ADMIN
+  GUEST @deprecated(reason: "use USER")
+}
+
+union Entity @tag(name: "entity") = User | Droid
+
+input UserFilter @oneOf {
+  id: ID @tag(name: "id")
+  name: String
+}`))
+	t.Run(parseSuccess(`query Hero($withFriends: Boolean!) @cached {
+  hero @include(if: $withFriends) @skip(if: false) {
+    ...HeroName @include(if: $withFriends)
+    ... @skip(if: false) {
+      id
+    }
+  }
+}
+
+fragment HeroName on Character @tag(name: "x") {
+  name
+}`))
+	t.Run(parseSuccess(`query Hero($id: ID! = "1000" @deprecated, $episode: Episode @tag(name: "x") @tag(name: "y")) {
+  hero(id: $id, episode: $episode) {
+    name
+  }
+}`))
+	t.Run(parseSuccess(`input Filter {
+  query: String! = "all"
+  limit: Int = 10 @deprecated
+  tags: [String!] = ["a", "b"]
+  order: Order = ASC
+}
+
+type Query {
+  search(filter: Filter = {
+    query: "go"
+    limit: 5
+  }): [String]
+}`))
+	t.Run(parseSuccess(`interface Node {
+  children(first: Int = 10, after: ID @deprecated): [Node]
+}`))
+}
+
+func TestParseImplements(t *testing.T) {
+	t.Run(parseSuccess(`interface Entity {
+  id: ID!
+}
+
+interface Node implements Entity {
+  id: ID!
+}
+
+type Human implements Character & Node @key(fields: "id") {
+  id: ID!
+}
+
+extend type Droid implements Node & Entity`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	for _, src := range []string{
+		`type Human implements & Character & Node { id: ID! }`,
+		`type Human implements Character Node { id: ID! }`,
+	} {
+		doc, err := p.ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed for %s", src) {
+			return
+		}
+
+		def := (<-doc.Definitions()).(model.ObjectDefinition)
+		var names []string
+		for typ := range def.Interfaces() {
+			names = append(names, typ.Name())
+		}
+		if !assert.Equal(t, []string{"Character", "Node"}, names, "interfaces should match") {
+			return
+		}
+		if !assert.True(t, def.Implements("Node"), "def.Implements should be true") {
+			return
+		}
+	}
+
+	// the legacy form stops at the start of the next definition
+	doc, err := p.ParseString(ctx, `extend type Human implements Character Node
+type Droid { id: ID! }`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+	if !assert.Len(t, doc.Definitions(), 2, "there should be 2 definitions") {
+		return
+	}
+
+	for _, src := range []string{
+		`type Human implements { id: ID! }`,
+		`type Human implements Character Node & Entity { id: ID! }`,
+		`type Human implements Character & Node Entity { id: ID! }`,
+		`type Human implements Character & { id: ID! }`,
+	} {
+		_, err := p.ParseString(ctx, src)
+		if !assert.Error(t, err, "p.Parse should fail for %s", src) {
+			return
+		}
+	}
+}
+
+func TestParseTypeExtension(t *testing.T) {
+	t.Run(parseSuccess(`extend type Query {
+  me: User
+}
+
+extend type User implements Node @key(fields: "id")
+
+extend interface Node @tag(name: "node") {
+  createdAt: String
+}
+
+extend union SearchResult = Planet | Vehicle
+
+extend enum Episode @tag(name: "episode") {
+  RESISTANCE
+}
+
+extend input ReviewInput {
+  rating: Int
+}
+
+extend scalar DateTime @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")
+
+extend schema @link(url: "https://example.com") {
+  subscription: Subscription
+}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	for _, src := range []string{
+		`extend type Query`,
+		`extend scalar DateTime`,
+		`extend schema`,
+		`extend schema {}`,
+		`extend directive @foo on FIELD`,
+		`"description" extend type Query { me: User }`,
+	} {
+		_, err := p.ParseString(ctx, src)
+		if !assert.Error(t, err, "p.Parse should fail for %s", src) {
+			return
+		}
+	}
+
+	t.Run("Empty extension position", func(t *testing.T) {
+		for _, src := range []string{
+			"extend type Query\n\ntype User {\n  name: String\n}",
+			"extend schema\n\ntype User {\n  name: String\n}",
+		} {
+			_, err := p.ParseString(ctx, src)
+			serr, ok := errors.Cause(err).(*parser.SyntaxError)
+			if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+				return
+			}
+			if !assert.Equal(t, parser.Position{Offset: 7, Line: 1, Column: 8}, serr.Token.Pos, "position should be the one of the extended kind") {
+				return
+			}
+		}
+	})
+}
+
+func TestMergeExtensions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	parse := func(src string) model.Document {
+		doc, err := p.ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			t.FailNow()
+		}
+		return doc
+	}
+
+	t.Run("Merge", func(t *testing.T) {
+		base := parse(`schema {
+  query: Query
+}
+
+type Query {
+  hero: String
+}
+
+enum Episode {
+  NEWHOPE
+  EMPIRE
+}
+
+union SearchResult = Human`)
+		ext := parse(`extend type Query @cached {
+  droid(id: ID!): String
+}
+
+extend enum Episode {
+  JEDI
+}
+
+extend union SearchResult = Droid
+
+extend schema {
+  mutation: Mutation
+}`)
+
+		doc, err := model.MergeExtensions(base, ext)
+		if !assert.NoError(t, err, "model.MergeExtensions should succeed") {
+			return
+		}
+
+		var buf bytes.Buffer
+		if !assert.NoError(t, format.GraphQL(ctx, &buf, doc), "format.GraphQL should succeed") {
+			return
+		}
+
+		expected := `schema {
+  query: Query
+  mutation: Mutation
+}
+
+type Query @cached {
+  hero: String
+  droid(id: ID!): String
+}
+
+enum Episode {
+  NEWHOPE
+  EMPIRE
+  JEDI
+}
+
+union SearchResult = Human | Droid`
+		if !assert.Equal(t, expected, buf.String(), "merged document should match") {
+			return
+		}
+
+		def, ok := doc.LookupType("Episode")
+		if !assert.True(t, ok, "doc.LookupType should succeed") {
+			return
+		}
+		var values []interface{}
+		for e := range def.(model.EnumDefinition).Elements() {
+			values = append(values, e.Value().Value())
+		}
+		if !assert.Equal(t, []interface{}{1, 2, 3}, values, "enum values should be unique") {
+			return
+		}
+	})
+
+	t.Run("Failure leaves documents unchanged", func(t *testing.T) {
+		base := parse(`type Query { hero: String } enum Episode { NEWHOPE EMPIRE }`)
+		first := parse(`extend type Query { droid: String }`)
+		second := parse(`extend enum Episode { EMPIRE }`)
+
+		_, err := model.MergeExtensions(base, first, second)
+		if !assert.Error(t, err, "model.MergeExtensions should fail") {
+			return
+		}
+
+		def, ok := base.LookupType("Query")
+		if !assert.True(t, ok, "base.LookupType should succeed") {
+			return
+		}
+		var fields []string
+		for f := range def.(model.ObjectDefinition).Fields() {
+			fields = append(fields, f.Name())
+		}
+		if !assert.Equal(t, []string{"hero"}, fields, "fields of the base definition should not change") {
+			return
+		}
+	})
+
+	t.Run("Extensions are not modified", func(t *testing.T) {
+		base := parse(`type Query { hero: String } enum Episode { NEWHOPE EMPIRE }`)
+		ext := parse(`extend enum Episode { JEDI }`)
+
+		for i := 0; i < 2; i++ {
+			doc, err := model.MergeExtensions(base, ext)
+			if !assert.NoError(t, err, "model.MergeExtensions should succeed") {
+				return
+			}
+			def, _ := doc.LookupType("Episode")
+			var values []interface{}
+			for e := range def.(model.EnumDefinition).Elements() {
+				values = append(values, e.Value().Value())
+			}
+			if !assert.Equal(t, []interface{}{1, 2, 3}, values, "enum values should match") {
+				return
+			}
+		}
+
+		def, _ := base.LookupType("Episode")
+		var names []string
+		for e := range def.(model.EnumDefinition).Elements() {
+			names = append(names, e.Name())
+		}
+		if !assert.Equal(t, []string{"NEWHOPE", "EMPIRE"}, names, "elements of the base definition should not change") {
+			return
+		}
+
+		for d := range ext.Definitions() {
+			for e := range d.(model.EnumExtension).Elements() {
+				if !assert.Equal(t, 1, e.Value().Value(), "value of the extension element should not change") {
+					return
+				}
+			}
+		}
+	})
+
+	for _, c := range []struct {
+		Name     string
+		Source   string
+		Expected string
+	}{
+		{
+			Name:     "Unknown type",
+			Source:   `extend type Mutation { foo: String }`,
+			Expected: `cannot extend unknown type Mutation`,
+		},
+		{
+			Name:     "Kind mismatch",
+			Source:   `extend enum Query { FOO }`,
+			Expected: `cannot extend Query as an enum`,
+		},
+		{
+			Name:     "Existing field",
+			Source:   `extend type Query { hero: String }`,
+			Expected: `field Query.hero is already defined`,
+		},
+		{
+			Name:     "Existing interface",
+			Source:   `extend type Query implements Node & Node`,
+			Expected: `type Query already implements Node`,
+		},
+		{
+			Name:     "Existing root operation type",
+			Source:   `extend schema { query: Query }`,
+			Expected: `schema already defines the query operation type`,
+		},
+	} {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			base := parse(`schema { query: Query } type Query { hero: String }`)
+			_, err := model.MergeExtensions(base, parse(c.Source))
+			if !assert.Error(t, err, "model.MergeExtensions should fail") {
+				return
+			}
+			if !assert.Contains(t, err.Error(), c.Expected, "error message should match") {
+				return
+			}
+		})
+	}
+}
+
+func TestParseTypeSystemDirectives(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	doc, err := p.ParseString(ctx, `type User @key(fields: "id") {
+  oldField: String @deprecated(reason: "x")
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	def := (<-doc.Definitions()).(model.ObjectDefinition)
+	directives := def.Directives()
+	if !assert.Len(t, directives, 1, "object should have 1 directive") {
+		return
+	}
+	if !assert.Equal(t, "key", (<-directives).Name(), "directive name should match") {
+		return
+	}
+
+	field := <-def.Fields()
+	directives = field.Directives()
+	if !assert.Len(t, directives, 1, "field should have 1 directive") {
+		return
+	}
+	d := <-directives
+	if !assert.Equal(t, "deprecated", d.Name(), "directive name should match") {
+		return
+	}
+	arg := <-d.Arguments()
+	if !assert.Equal(t, "x", arg.Value().Value(), "directive argument should match") {
+		return
+	}
+}
+
+func TestParseDirectiveDefinition(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	doc, err := p.ParseString(ctx, `directive @tag(name: String!) on
+  | FIELD
+  | FRAGMENT_SPREAD`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	def, ok := doc.LookupDirective("tag")
+	if !assert.True(t, ok, "doc.LookupDirective should succeed") {
+		return
+	}
+	if !assert.Equal(t, []model.DirectiveLocation{model.DirectiveLocationField, model.DirectiveLocationFragmentSpread}, def.Locations(), "locations should match") {
+		return
+	}
+	if !assert.False(t, def.IsRepeatable(), "directive should not be repeatable") {
+		return
+	}
+
+	for _, src := range []string{
+		`directive @tag on`,
+		`directive @tag on FIELD | NOWHERE`,
+		`directive tag on FIELD`,
+	} {
+		_, err := p.ParseString(ctx, src)
+		if !assert.Error(t, err, "p.Parse should fail for %s", src) {
+			return
+		}
+	}
+}
+
+func TestParseSchemaTypes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	const src = `schema {
+  query: Foo
+  types: [Bar, Baz, Quux]
+}`
+
+	t.Run("Rejected by default", func(t *testing.T) {
+		_, err := parser.New().ParseString(ctx, src)
+		if !assert.Error(t, err, "p.Parse should fail") {
+			return
+		}
+	})
+	t.Run("Accepted with WithSchemaTypes", func(t *testing.T) {
+		doc, err := parser.New(parser.WithSchemaTypes(true)).ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return
+		}
+
+		var buf bytes.Buffer
+		if !assert.NoError(t, format.GraphQL(ctx, &buf, doc), "format.GraphQL should be successful") {
+			return
+		}
+
+		if !assert.Equal(t, src, buf.String(), "formatted code should be identical") {
+			return
+		}
+	})
+	t.Run("Empty schema", func(t *testing.T) {
+		_, err := parser.New().ParseString(ctx, "schema {\n}")
+		serr, ok := errors.Cause(err).(*parser.SyntaxError)
+		if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+			return
+		}
+		if !assert.Equal(t, parser.BRACE_R, serr.Token.Type, "error should be reported on the closing brace") {
+			return
+		}
+		if !assert.Equal(t, 2, serr.Token.Pos.Line, "line should match") {
+			return
+		}
+	})
 }
  
+
+func TestParseComments(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	doc, err := p.ParseString(ctx, `# The hero of the saga
+query Hero { # trailing comment
+  # comment before a field
+  hero {
+    name # the name
+  }
+}
+# end of file`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, format.GraphQL(ctx, &buf, doc), "format.GraphQL should be successful") {
+		return
+	}
+
+	const expected = `query Hero {
+  hero {
+    name
+  }
+}`
+	if !assert.Equal(t, expected, buf.String(), "comments should be skipped") {
+		return
+	}
+}
+
+func TestParseConstValue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	for _, src := range []string{
+		`query Q($a: [Int] = [$b]) { hero { name } }`,
+		`query Q($a: Input = {b: [1, $c]}) { hero { name } }`,
+		`type Query { hero(a: [Int] = [$b]): String }`,
+	} {
+		_, err := p.ParseString(ctx, src)
+		if !assert.Error(t, err, "variables in constant values should fail") {
+			return
+		}
+	}
+}
+
+func TestParseStringValue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	doc, err := p.ParseString(ctx, `query Q {
+  search(
+    a: "simple"
+    b: "escaped \\ \" \/ \b\f\n\r\t"
+    c: "unicode \u00e9\u3042 \uD83D\uDE00"
+    d: """
+      Hello,
+        World!
+
+      Bye \""" "quoted"
+    """
+    e: """  no common indent on the first line
+    second line"""
+    f: ""
+    g: """"""
+  ) {
+    id
+  }
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	op, ok := doc.LookupQuery("Q")
+	if !assert.True(t, ok, "query Q should exist") {
+		return
+	}
+	field := (<-op.Selections()).(model.SelectionField)
+
+	expected := map[string]string{
+		"a": "simple",
+		"b": "escaped \\ \" / \b\f\n\r\t",
+		"c": "unicode \u00e9\u3042 \U0001F600",
+		"d": "Hello,\n  World!\n\nBye \"\"\" \"quoted\"",
+		"e": "  no common indent on the first line\nsecond line",
+		"f": "",
+		"g": "",
+	}
+	for arg := range field.Arguments() {
+		if !assert.Equal(t, expected[arg.Name()], arg.Value().Value(), "value of %s should match", arg.Name()) {
+			return
+		}
+	}
+
+	invalid := []struct {
+		Name  string
+		Value string
+		Error string
+	}{
+		{Name: "Unknown escape", Value: `"\x41"`, Error: "unexpected token ILLEGAL"},
+		{Name: "Lone high surrogate", Value: `"\uD800"`, Error: "invalid escape sequence"},
+		{Name: "Lone low surrogate", Value: `"\uDC00x"`, Error: "invalid escape sequence"},
+		{Name: "Mismatched surrogates", Value: `"\uD800\u0041"`, Error: "invalid escape sequence"},
+	}
+	for _, tc := range invalid {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := p.ParseString(ctx, `{ search(a: `+tc.Value+`) { id } }`)
+			if !assert.Error(t, err, "p.Parse should fail") {
+				return
+			}
+			serr, ok := errors.Cause(err).(*parser.SyntaxError)
+			if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+				return
+			}
+			if !assert.Contains(t, serr.Error(), tc.Error, "message should match") {
+				return
+			}
+		})
+	}
+}
+
+func TestParseDescription(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+	doc, err := p.ParseString(ctx, `"""
+  The query root
+"""
+type Query {
+  "Look up a hero"
+  hero("The film" episode: Episode): String
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	def, ok := doc.LookupType("Query")
+	if !assert.True(t, ok, "type Query should exist") {
+		return
+	}
+	obj := def.(model.ObjectDefinition)
+	if !assert.Equal(t, "The query root", obj.Description(), "type description should match") {
+		return
+	}
+	if !assert.Equal(t, 1, obj.Location().Start.Line, "type location should start at the description") {
+		return
+	}
+
+	field := <-obj.Fields()
+	if !assert.Equal(t, "Look up a hero", field.Description(), "field description should match") {
+		return
+	}
+	arg := <-field.Arguments()
+	if !assert.Equal(t, "The film", arg.Description(), "argument description should match") {
+		return
+	}
+
+	t.Run("Description before schema", func(t *testing.T) {
+		doc, err := p.ParseString(ctx, `"The Star Wars schema" schema { query: Query }`)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return
+		}
+
+		schema := (<-doc.Definitions()).(model.Schema)
+		if !assert.Equal(t, "The Star Wars schema", schema.Description(), "schema description should match") {
+			return
+		}
+		if !assert.Equal(t, 1, schema.Location().Start.Column, "schema location should start at the description") {
+			return
+		}
+	})
+	t.Run("Description before an operation", func(t *testing.T) {
+		_, err := p.ParseString(ctx, `"The hero" query { hero { name } }`)
+		if !assert.Error(t, err, "p.Parse should fail") {
+			return
+		}
+	})
+}
+
+func TestLocation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New(parser.WithSourceName("hero.graphql"))
+	doc, err := p.ParseString(ctx, `query Hero($ep: Episode!) {
+  hero(episode: $ep) {
+    ...HeroFields
+  }
+}
+
+type Query {
+  hero(episode: Episode): [Character]!
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	pos := func(line, column int) model.Position {
+		return model.Position{Line: line, Column: column}
+	}
+	check := func(t *testing.T, n model.Locator, start, end model.Position) {
+		loc := n.Location()
+		assert.Equal(t, "hero.graphql", loc.Source, "source name should match")
+		assert.Equal(t, start.Line, loc.Start.Line, "start line should match")
+		assert.Equal(t, start.Column, loc.Start.Column, "start column should match")
+		assert.Equal(t, end.Line, loc.End.Line, "end line should match")
+		assert.Equal(t, end.Column, loc.End.Column, "end column should match")
+	}
+
+	op, ok := doc.LookupQuery("Hero")
+	if !assert.True(t, ok, "query Hero should exist") {
+		return
+	}
+	t.Run("OperationDefinition", func(t *testing.T) {
+		check(t, op, pos(1, 1), pos(5, 2))
+		assert.Equal(t, 0, op.Location().Start.Offset, "start offset should match")
+	})
+	t.Run("VariableDefinition", func(t *testing.T) {
+		v := <-op.Variables()
+		check(t, v, pos(1, 12), pos(1, 25))
+		check(t, v.Type().(model.Locator), pos(1, 17), pos(1, 25))
+	})
+
+	hero := (<-op.Selections()).(model.SelectionField)
+	t.Run("SelectionField", func(t *testing.T) {
+		check(t, hero, pos(2, 3), pos(4, 4))
+	})
+	t.Run("Argument", func(t *testing.T) {
+		arg := <-hero.Arguments()
+		check(t, arg, pos(2, 8), pos(2, 20))
+		check(t, arg.Value(), pos(2, 17), pos(2, 20))
+	})
+	t.Run("FragmentSpread", func(t *testing.T) {
+		spread := (<-hero.Selections()).(model.FragmentSpread)
+		check(t, spread, pos(3, 5), pos(3, 18))
+	})
+
+	t.Run("ObjectDefinition", func(t *testing.T) {
+		def, ok := doc.LookupType("Query")
+		if !assert.True(t, ok, "type Query should exist") {
+			return
+		}
+		obj := def.(model.ObjectDefinition)
+		check(t, obj, pos(7, 1), pos(9, 2))
+
+		f := <-obj.Fields()
+		check(t, f, pos(8, 3), pos(8, 39))
+		check(t, <-f.Arguments(), pos(8, 8), pos(8, 24))
+		check(t, f.Type().(model.Locator), pos(8, 27), pos(8, 39))
+	})
+}
+
+func TestSyntaxError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New(parser.WithSourceName("hero.graphql"))
+	_, err := p.ParseString(ctx, "query Hero {\n  hero(id: ) {\n    name\n  }\n}")
+	if !assert.Error(t, err, "p.Parse should fail") {
+		return
+	}
+
+	serr, ok := errors.Cause(err).(*parser.SyntaxError)
+	if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+		return
+	}
+	if !assert.Equal(t, parser.PAREN_R, serr.Token.Type, "token should match") {
+		return
+	}
+	if !assert.Equal(t, parser.Position{Offset: 24, Line: 2, Column: 12}, serr.Token.Pos, "position should match") {
+		return
+	}
+	if !assert.Equal(t, "hero.graphql", serr.Source, "source should match") {
+		return
+	}
+	if !assert.Equal(t, "2 |   hero(id: ) {\n  |            ^", serr.Frame, "code frame should match") {
+		return
+	}
+	if !assert.Equal(t, "value: unexpected token PAREN_R ()) at line 2, column 12", serr.Error(), "message should match") {
+		return
+	}
+
+	_, err = p.ParseString(ctx, "query Hero {\n\thero {\n\t\tname: }\n}")
+	serr, ok = errors.Cause(err).(*parser.SyntaxError)
+	if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+		return
+	}
+	if !assert.Equal(t, []parser.TokenType{parser.NAME}, serr.Expected, "expected tokens should match") {
+		return
+	}
+	if !assert.Equal(t, "3 | \t\tname: }\n  | \t\t      ^", serr.Frame, "code frame should keep tabs") {
+		return
+	}
+}
+
+func TestParseErrorRecovery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New(parser.WithErrorRecovery(true))
+	doc, err := p.ParseString(ctx, `query Hero {
+  hero(id: ) {
+    name
+  }
+  droid {
+    id
+    ... on {
+    }
+    name
+  }
+}
+
+type Query {
+  hero: 
+}
+
+fragment Name on Character {
+  name
+}
+
+query Unterminated {
+  hero {
+    name
+`)
+	if !assert.Error(t, err, "p.Parse should fail") {
+		return
+	}
+
+	serrs, ok := err.(parser.SyntaxErrors)
+	if !assert.True(t, ok, "error should be parser.SyntaxErrors") {
+		return
+	}
+
+	var positions []parser.Position
+	for _, serr := range serrs {
+		positions = append(positions, serr.Token.Pos)
+	}
+	expected := []parser.Position{
+		{Offset: 24, Line: 2, Column: 12},
+		{Offset: 69, Line: 7, Column: 12},
+		{Offset: 115, Line: 15, Column: 1},
+		{Offset: 196, Line: 24, Column: 1},
+	}
+	if !assert.Equal(t, expected, positions, "error positions should match") {
+		t.Logf("%s", serrs)
+		return
+	}
+
+	if !assert.NotNil(t, doc, "partial document should be returned") {
+		return
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, format.GraphQL(ctx, &buf, doc), "format.GraphQL should be successful") {
+		return
+	}
+
+	const formatted = `query Hero {
+  droid {
+    id
+    name
+  }
+}
+
+fragment Name on Character {
+  name
+}
+
+query Unterminated {
+  hero {
+    name
+  }
+}`
+	if !assert.Equal(t, formatted, buf.String(), "partial document should match") {
+		t.Logf("%s", buf.String())
+		return
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	const src = `"The ✓ query root"
+type Query {
+  hero(episode: Episode = JEDI): Character
+}
+
+query Hero($episode: Episode) {
+  hero(episode: $episode) {
+    name
+  }
+}`
+
+	p := parser.New()
+	expected, err := p.ParseString(ctx, src)
+	if !assert.NoError(t, err, "p.ParseString should succeed") {
+		return
+	}
+
+	doc, err := p.ParseReader(ctx, iotest.OneByteReader(strings.NewReader(src)))
+	if !assert.NoError(t, err, "p.ParseReader should succeed") {
+		return
+	}
+
+	var buf1, buf2 bytes.Buffer
+	if !assert.NoError(t, format.GraphQL(ctx, &buf1, expected), "format.GraphQL should be successful") {
+		return
+	}
+	if !assert.NoError(t, format.GraphQL(ctx, &buf2, doc), "format.GraphQL should be successful") {
+		return
+	}
+	if !assert.Equal(t, buf1.String(), buf2.String(), "documents should match") {
+		return
+	}
+
+	locations := func(doc model.Document) map[model.Location]struct{} {
+		m := make(map[model.Location]struct{})
+		for def := range doc.Definitions() {
+			m[def.(model.Locator).Location()] = struct{}{}
+		}
+		return m
+	}
+	if !assert.Equal(t, locations(expected), locations(doc), "locations should match") {
+		return
+	}
+
+	t.Run("Syntax error", func(t *testing.T) {
+		_, err := p.ParseReader(ctx, iotest.OneByteReader(strings.NewReader("query Hero {\n  hero(id: ) {\n    name\n  }\n}")))
+		serr, ok := errors.Cause(err).(*parser.SyntaxError)
+		if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+			return
+		}
+		if !assert.Equal(t, "2 |   hero(id: ) {\n  |            ^", serr.Frame, "code frame should match") {
+			return
+		}
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		_, err := p.ParseReader(ctx, iotest.TimeoutReader(strings.NewReader("query Hero {\n  hero {\n    name\n  }\n}")))
+		if !assert.Error(t, err, "p.ParseReader should fail") {
+			return
+		}
+		if !assert.Equal(t, iotest.ErrTimeout, errors.Cause(err), "cause should be the read error") {
+			return
+		}
+	})
+}
+
+func TestParseLimits(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	const src = `query Hero {
+  hero(filter: {ids: [[1, 2]]}) {
+    friends {
+      name
+    }
+  }
+}`
+
+	for _, tc := range []struct {
+		name     string
+		option   parser.Option
+		expected parser.LimitError
+	}{
+		{
+			name:     "Tokens",
+			option:   parser.WithMaxTokens(10),
+			expected: parser.LimitError{Limit: parser.LimitTokens, Max: 10, Pos: parser.Position{Offset: 34, Line: 2, Column: 22}},
+		},
+		{
+			name:     "Depth",
+			option:   parser.WithMaxDepth(3),
+			expected: parser.LimitError{Limit: parser.LimitDepth, Max: 3, Pos: parser.Position{Offset: 35, Line: 2, Column: 23}},
+		},
+		{
+			name:     "Source size",
+			option:   parser.WithMaxSourceSize(32),
+			expected: parser.LimitError{Limit: parser.LimitSourceSize, Max: 32, Pos: parser.Position{Offset: 32}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := parser.New(tc.option)
+			for _, parse := range []func() (model.Document, error){
+				func() (model.Document, error) { return p.ParseString(ctx, src) },
+				func() (model.Document, error) { return p.ParseReader(ctx, strings.NewReader(src)) },
+			} {
+				_, err := parse()
+				lerr, ok := err.(*parser.LimitError)
+				if !assert.True(t, ok, "error should be a *parser.LimitError") {
+					t.Logf("%s", err)
+					return
+				}
+				if !assert.Equal(t, tc.expected, *lerr, "error should match") {
+					return
+				}
+			}
+		})
+	}
+
+	t.Run("Within limits", func(t *testing.T) {
+		p := parser.New(parser.WithMaxTokens(30), parser.WithMaxDepth(4), parser.WithMaxSourceSize(len(src)))
+		if _, err := p.ParseString(ctx, src); !assert.NoError(t, err, "p.ParseString should succeed") {
+			return
+		}
+		if _, err := p.ParseReader(ctx, strings.NewReader(src)); !assert.NoError(t, err, "p.ParseReader should succeed") {
+			return
+		}
+	})
+
+	t.Run("Deep nesting", func(t *testing.T) {
+		p := parser.New(parser.WithMaxDepth(64), parser.WithErrorRecovery(true))
+		_, err := p.ParseString(ctx, "query "+strings.Repeat("{ a ", 100000))
+		if _, ok := err.(*parser.LimitError); !assert.True(t, ok, "error should be a *parser.LimitError") {
+			return
+		}
+	})
+}
+
+func TestParseCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := parser.New()
+	_, err := p.ParseString(ctx, `query { hero { name } }`)
+	if !assert.Equal(t, context.Canceled, err, "error should be context.Canceled") {
+		return
+	}
+}
+
+func TestParseFragments(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := parser.New()
+
+	t.Run("Value", func(t *testing.T) {
+		v, err := p.ParseValue(ctx, []byte(`{ids: [1, $id]}`))
+		if !assert.NoError(t, err, "p.ParseValue should succeed") {
+			return
+		}
+		if !assert.Equal(t, model.ObjectKind, v.Kind(), "kind should match") {
+			return
+		}
+	})
+
+	t.Run("Const value", func(t *testing.T) {
+		v, err := p.ParseConstValue(ctx, []byte(`"hello"`))
+		if !assert.NoError(t, err, "p.ParseConstValue should succeed") {
+			return
+		}
+		if !assert.Equal(t, "hello", v.Value(), "value should match") {
+			return
+		}
+
+		_, err = p.ParseConstValue(ctx, []byte(`[1, $id]`))
+		if !assert.Error(t, err, "variables in constant values should fail") {
+			return
+		}
+	})
+
+	t.Run("Type", func(t *testing.T) {
+		typ, err := p.ParseType(ctx, []byte(`[String!]!`))
+		if !assert.NoError(t, err, "p.ParseType should succeed") {
+			return
+		}
+		lt, ok := typ.(model.ListType)
+		if !assert.True(t, ok, "type should be a list type") {
+			return
+		}
+		if !assert.False(t, lt.IsNullable(), "list should not be nullable") {
+			return
+		}
+		nt, ok := lt.Type().(model.NamedType)
+		if !assert.True(t, ok, "element type should be a named type") {
+			return
+		}
+		if !assert.Equal(t, "String", nt.Name(), "name should match") {
+			return
+		}
+		if !assert.False(t, nt.IsNullable(), "element should not be nullable") {
+			return
+		}
+	})
+
+	t.Run("Selection set", func(t *testing.T) {
+		set, err := p.ParseSelectionSet(ctx, []byte("{\n  hero { name }\n  ...Friends\n}"))
+		if !assert.NoError(t, err, "p.ParseSelectionSet should succeed") {
+			return
+		}
+		if !assert.Len(t, set, 2, "selections should match") {
+			return
+		}
+	})
+
+	t.Run("Trailing tokens", func(t *testing.T) {
+		for _, tc := range []struct {
+			name  string
+			parse func() error
+			pos   parser.Position
+		}{
+			{
+				name: "Value",
+				parse: func() error {
+					_, err := p.ParseValue(ctx, []byte(`1 2`))
+					return err
+				},
+				pos: parser.Position{Offset: 2, Line: 1, Column: 3},
+			},
+			{
+				name: "Type",
+				parse: func() error {
+					_, err := p.ParseType(ctx, []byte("String!\n]"))
+					return err
+				},
+				pos: parser.Position{Offset: 8, Line: 2, Column: 1},
+			},
+			{
+				name: "Selection set",
+				parse: func() error {
+					_, err := p.ParseSelectionSet(ctx, []byte(`{ name } }`))
+					return err
+				},
+				pos: parser.Position{Offset: 9, Line: 1, Column: 10},
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				err := tc.parse()
+				if !assert.Error(t, err, "parsing with trailing tokens should fail") {
+					return
+				}
+				serr, ok := errors.Cause(err).(*parser.SyntaxError)
+				if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+					return
+				}
+				if !assert.Equal(t, tc.pos, serr.Token.Pos, "position should match") {
+					return
+				}
+				if !assert.Equal(t, []parser.TokenType{parser.EOF}, serr.Expected, "expected tokens should match") {
+					return
+				}
+			})
+		}
+	})
+
+	t.Run("Frame", func(t *testing.T) {
+		_, err := p.ParseSelectionSet(ctx, []byte("{\n  hero(id: ) { name }\n}"))
+		serr, ok := errors.Cause(err).(*parser.SyntaxError)
+		if !assert.True(t, ok, "cause should be a *parser.SyntaxError") {
+			return
+		}
+		if !assert.Equal(t, "2 |   hero(id: ) { name }\n  |            ^", serr.Frame, "code frame should match") {
+			return
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// stringValue converts the source text of a STRING token (including
+// the surrounding quotes) into the string value that it represents
+func stringValue(raw string) (string, error) {
+	if strings.HasPrefix(raw, `"""`) {
+		if len(raw) < 6 || !strings.HasSuffix(raw, `"""`) {
+			return "", errors.New(`unterminated block string`)
+		}
+		return blockStringValue(raw[3 : len(raw)-3]), nil
+	}
+
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return "", errors.New(`unterminated string`)
+	}
+	return unescapeString(raw[1 : len(raw)-1])
+}
+
+// unescapeString decodes the escape sequences in the contents of
+// a regular (non-block) string
+func unescapeString(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var buf bytes.Buffer
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '\\')
+		if i < 0 {
+			buf.WriteString(s)
+			break
+		}
+		buf.WriteString(s[:i])
+		s = s[i:]
+
+		if len(s) < 2 {
+			return "", errors.New(`invalid escape sequence`)
+		}
+
+		switch c := s[1]; c {
+		case '"', '\\', '/':
+			buf.WriteByte(c)
+		case 'b':
+			buf.WriteByte('\b')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case 'u':
+			r, n, err := unescapeUnicode(s)
+			if err != nil {
+				return "", err
+			}
+			buf.WriteRune(r)
+			s = s[n:]
+			continue
+		default:
+			return "", errors.Errorf(`invalid escape sequence \%c`, c)
+		}
+		s = s[2:]
+	}
+	return buf.String(), nil
+}
+
+// unescapeUnicode decodes the \uXXXX sequence at the beginning of `s`,
+// including surrogate pairs written as two consecutive sequences.
+// Returns the rune, and the number of bytes consumed. A surrogate that
+// is not part of a valid pair is an error
+func unescapeUnicode(s string) (rune, int, error) {
+	hex := func(s string) (rune, bool) {
+		if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+			return 0, false
+		}
+		v, err := strconv.ParseUint(s[2:6], 16, 16)
+		if err != nil {
+			return 0, false
+		}
+		return rune(v), true
+	}
+
+	r, ok := hex(s)
+	if !ok {
+		return 0, 0, errors.New(`invalid unicode escape sequence`)
+	}
+
+	if utf16.IsSurrogate(r) {
+		if r2, ok := hex(s[6:]); ok {
+			if d := utf16.DecodeRune(r, r2); d != utf8.RuneError {
+				return d, 12, nil
+			}
+		}
+		return 0, 0, errors.Errorf(`invalid escape sequence %s: unpaired surrogate`, s[:6])
+	}
+	return r, 6, nil
+}
+
+// blockStringValue computes the value of a block string from its raw
+// contents (without the surrounding """), as described in the GraphQL
+// specification: escaped triple quotes are restored, the common
+// indentation is removed from all lines but the first, and leading and
+// trailing blank lines are removed
+func blockStringValue(raw string) string {
+	raw = strings.Replace(raw, `\"""`, `"""`, -1)
+	raw = strings.Replace(raw, "\r\n", "\n", -1)
+	lines := strings.Split(strings.Replace(raw, "\r", "\n", -1), "\n")
+
+	commonIndent := -1
+	for _, line := range lines[1:] {
+		indent := leadingWhitespace(line)
+		if indent == len(line) {
+			continue
+		}
+		if commonIndent < 0 || indent < commonIndent {
+			commonIndent = indent
+		}
+	}
+
+	if commonIndent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) < commonIndent {
+				lines[i] = ""
+			} else {
+				lines[i] = lines[i][commonIndent:]
+			}
+		}
+	}
+
+	for len(lines) > 0 && isBlank(lines[0]) {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func leadingWhitespace(s string) int {
+	i := 0
+	for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+func isBlank(s string) bool {
+	return leadingWhitespace(s) == len(s)
+}
@@ -20,16 +20,18 @@ const (
 	BRACKET_R                     // ]
 	BRACE_L                       // {
 	PIPE                          // |
+	AMP                           // &
 	BRACE_R                       // }
 	NAME                          // Name
 	INT                           // Int
 	FLOAT                         // Float
 	STRING                        // String
+	COMMENT                       // Comment, only emitted when requested
 	TokenTypeMax                  // Max value for token types
 )
 
 func (tt TokenType) String() string {
-	const s = "ILLEGALIGNORABLEEOFBANGDOLLARPAREN_LPAREN_RSPREADCOLONEQUALSATBRACKET_LBRACKET_RBRACE_LPIPEBRACE_RNAMEINTFLOATSTRINGTokenTypeMax"
+	const s = "ILLEGALIGNORABLEEOFBANGDOLLARPAREN_LPAREN_RSPREADCOLONEQUALSATBRACKET_LBRACKET_RBRACE_LPIPEAMPBRACE_RNAMEINTFLOATSTRINGCOMMENTTokenTypeMax"
 	switch tt {
 	case ILLEGAL:
 		return s[0:7]
@@ -61,18 +63,22 @@ func (tt TokenType) String() string {
 		return s[80:87]
 	case PIPE:
 		return s[87:91]
+	case AMP:
+		return s[91:94]
 	case BRACE_R:
-		return s[91:98]
+		return s[94:101]
 	case NAME:
-		return s[98:102]
+		return s[101:105]
 	case INT:
-		return s[102:105]
+		return s[105:108]
 	case FLOAT:
-		return s[105:110]
+		return s[108:113]
 	case STRING:
-		return s[110:116]
+		return s[113:119]
+	case COMMENT:
+		return s[119:126]
 	case TokenTypeMax:
-		return s[116:128]
+		return s[126:138]
 	default:
 		return "invalid"
 	}
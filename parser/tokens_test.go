@@ -99,6 +99,12 @@ func TestTokenTypeString(t *testing.T) {
 			return
 		}
 	})
+	t.Run("AMP", func(t *testing.T) {
+		tok := parser.AMP
+		if !assert.Equal(t, "AMP", tok.String(), "strings match") {
+			return
+		}
+	})
 	t.Run("BRACE_R", func(t *testing.T) {
 		tok := parser.BRACE_R
 		if !assert.Equal(t, "BRACE_R", tok.String(), "strings match") {
@@ -129,6 +135,12 @@ func TestTokenTypeString(t *testing.T) {
 			return
 		}
 	})
+	t.Run("COMMENT", func(t *testing.T) {
+		tok := parser.COMMENT
+		if !assert.Equal(t, "COMMENT", tok.String(), "strings match") {
+			return
+		}
+	})
 	t.Run("TokenTypeMax", func(t *testing.T) {
 		tok := parser.TokenTypeMax
 		if !assert.Equal(t, "TokenTypeMax", tok.String(), "strings match") {
@@ -51,7 +51,7 @@ func init() {
 			`friends`,
 			List(characterInterfaceDef.Type()),
 			Description(`'The friends of the human, or an empty list if they have none.`),
-			// resolve: human => getFriends(human),
+			FieldResolver(getFriends),
 		),
 		ObjectField(
 			`appearsIn`,
@@ -67,9 +67,7 @@ func init() {
 			`secretBackstory`,
 			String(),
 			Description(`Where are they from and how they came to be who they are.`),
-			// resolve() {
-			//  throw new Error('secretBackstory is secret.');
-			//},
+			FieldResolver(getSecretBackstory),
 		),
 	).Type()
 
@@ -90,7 +88,7 @@ func init() {
 			`friends`,
 			List(characterInterfaceDef.Type()),
 			Description(`'The friends of the droid, or an empty list if they have none.`),
-			// resolve: droid => getFriends(droid),
+			FieldResolver(getFriends),
 		),
 		ObjectField(
 			`appearsIn`,
@@ -101,9 +99,7 @@ func init() {
 			`secretBackstory`,
 			String(),
 			Description(`Where are they from and how they came to be who they are.`),
-			// resolve() {
-			//  throw new Error('secretBackstory is secret.');
-			//},
+			FieldResolver(getSecretBackstory),
 		),
 		ObjectField(
 			`primaryFunction`,
@@ -114,6 +110,16 @@ func init() {
 
 	var characterInterface = characterInterfaceDef.Configure(
 		Description(`A character in the Star Wars Trilogy`),
+		TypeResolver(func(v interface{}) model.Type {
+			character, _ := v.(map[string]interface{})
+			switch character["type"] {
+			case "Human":
+				return humanType
+			case "Droid":
+				return droidType
+			}
+			return nil
+		}),
 		InterfaceField(
 			`id`,
 			NotNull(String()),
@@ -141,18 +147,6 @@ func init() {
 		),
 	).Type()
 
-	/*
-	     resolveType(character) {
-	       if (character.type === 'Human') {
-	         return humanType;
-	       }
-	       if (character.type === 'Droid') {
-	         return droidType;
-	       }
-	     }
-	   });
-	*/
-
 	var queryType = queryTypeDef.Configure(
 		ObjectField(
 			`hero`,
@@ -162,7 +156,7 @@ func init() {
 				episodeEnum,
 				Description(`If omitted, returns the hero of the whole saga. If provided, returns the hero of that particular episode.`),
 			),
-			//resolve: (root, { episode }) => getHero(episode),
+			FieldResolver(getHero),
 		),
 		ObjectField(
 			`human`,
@@ -172,7 +166,7 @@ func init() {
 				NotNull(String()),
 				Description(`id of the human`),
 			),
-			// resolve: (root, { id }) => getHuman(id),
+			FieldResolver(getHuman),
 		),
 		ObjectField(
 			`droid`,
@@ -182,7 +176,7 @@ func init() {
 				NotNull(String()),
 				Description(`id of the droid`),
 			),
-			// resolve: (root, { id }) => getDroid(id),
+			FieldResolver(getDroid),
 		),
 	).Type()
 
@@ -0,0 +1,139 @@
+package schema
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// This is the equivalent of https://github.com/graphql/graphql-js/blob/master/src/__tests__/starWarsData.js
+// Characters are represented as plain maps, so that the fields without
+// an explicit resolver can be looked up by name
+
+var luke = map[string]interface{}{
+	"type":       "Human",
+	"id":         "1000",
+	"name":       "Luke Skywalker",
+	"friends":    []string{"1002", "1003", "2000", "2001"},
+	"appearsIn":  []int{4, 5, 6},
+	"homePlanet": "Tatooine",
+}
+
+var vader = map[string]interface{}{
+	"type":       "Human",
+	"id":         "1001",
+	"name":       "Darth Vader",
+	"friends":    []string{"1004"},
+	"appearsIn":  []int{4, 5, 6},
+	"homePlanet": "Tatooine",
+}
+
+var han = map[string]interface{}{
+	"type":      "Human",
+	"id":        "1002",
+	"name":      "Han Solo",
+	"friends":   []string{"1000", "1003", "2001"},
+	"appearsIn": []int{4, 5, 6},
+}
+
+var leia = map[string]interface{}{
+	"type":       "Human",
+	"id":         "1003",
+	"name":       "Leia Organa",
+	"friends":    []string{"1000", "1002", "2000", "2001"},
+	"appearsIn":  []int{4, 5, 6},
+	"homePlanet": "Alderaan",
+}
+
+var tarkin = map[string]interface{}{
+	"type":      "Human",
+	"id":        "1004",
+	"name":      "Wilhuff Tarkin",
+	"friends":   []string{"1001"},
+	"appearsIn": []int{4},
+}
+
+var threepio = map[string]interface{}{
+	"type":            "Droid",
+	"id":              "2000",
+	"name":            "C-3PO",
+	"friends":         []string{"1000", "1002", "1003", "2001"},
+	"appearsIn":       []int{4, 5, 6},
+	"primaryFunction": "Protocol",
+}
+
+var artoo = map[string]interface{}{
+	"type":            "Droid",
+	"id":              "2001",
+	"name":            "R2-D2",
+	"friends":         []string{"1000", "1002", "1003"},
+	"appearsIn":       []int{4, 5, 6},
+	"primaryFunction": "Astromech",
+}
+
+var humanData = map[string]map[string]interface{}{
+	"1000": luke,
+	"1001": vader,
+	"1002": han,
+	"1003": leia,
+	"1004": tarkin,
+}
+
+var droidData = map[string]map[string]interface{}{
+	"2000": threepio,
+	"2001": artoo,
+}
+
+func getCharacter(id string) map[string]interface{} {
+	if c, ok := humanData[id]; ok {
+		return c
+	}
+	if c, ok := droidData[id]; ok {
+		return c
+	}
+	return nil
+}
+
+func getFriends(_ context.Context, source interface{}, _ map[string]interface{}) (interface{}, error) {
+	character, ok := source.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf(`expected character, got %T`, source)
+	}
+
+	ids, _ := character["friends"].([]string)
+	friends := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if c := getCharacter(id); c != nil {
+			friends = append(friends, c)
+		}
+	}
+	return friends, nil
+}
+
+func getHero(_ context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+	if episode, ok := args["episode"]; ok && episode == 5 {
+		// Luke is the hero of Episode V
+		return luke, nil
+	}
+	// Artoo is the hero otherwise
+	return artoo, nil
+}
+
+func getHuman(_ context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+	id, _ := args["id"].(string)
+	if h, ok := humanData[id]; ok {
+		return h, nil
+	}
+	return nil, nil
+}
+
+func getDroid(_ context.Context, _ interface{}, args map[string]interface{}) (interface{}, error) {
+	id, _ := args["id"].(string)
+	if d, ok := droidData[id]; ok {
+		return d, nil
+	}
+	return nil, nil
+}
+
+func getSecretBackstory(_ context.Context, _ interface{}, _ map[string]interface{}) (interface{}, error) {
+	return nil, errors.New(`secretBackstory is secret.`)
+}
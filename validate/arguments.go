@@ -0,0 +1,126 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// knownArgumentNames checks that each argument given to a field or a
+// directive is defined by that field or directive
+func knownArgumentNames(ctx *validationCtx) *visitor.Handler {
+	h := &visitor.Handler{
+		EnterSelectionField: func(_ context.Context, v model.SelectionField) error {
+			fdef := ctx.fieldDef()
+			if fdef == nil {
+				return nil
+			}
+
+			defined := make(map[string]struct{})
+			for adef := range fdef.Arguments() {
+				defined[adef.Name()] = struct{}{}
+			}
+
+			for arg := range v.Arguments() {
+				if _, ok := defined[arg.Name()]; !ok {
+					ctx.reportf(arg, `Unknown argument "%s" on field "%s.%s".`, arg.Name(), ctx.parentType().Name(), v.Name())
+				}
+			}
+			return nil
+		},
+	}
+
+	return withDirectives(withTypeInfo(ctx, h), func(d model.Directive, _ model.DirectiveLocation) {
+		def, ok := ctx.lookupDirective(d.Name())
+		if !ok {
+			return
+		}
+
+		defined := make(map[string]struct{})
+		for adef := range def.Arguments() {
+			defined[adef.Name()] = struct{}{}
+		}
+
+		for arg := range d.Arguments() {
+			if _, ok := defined[arg.Name()]; !ok {
+				ctx.reportf(arg, `Unknown argument "%s" on directive "@%s".`, arg.Name(), d.Name())
+			}
+		}
+	})
+}
+
+// uniqueArgumentNames checks that no argument is given more than once
+// to a field or a directive
+func uniqueArgumentNames(ctx *validationCtx) *visitor.Handler {
+	check := func(ch chan model.Argument) {
+		seen := make(map[string]model.Argument)
+		for arg := range ch {
+			if prev, ok := seen[arg.Name()]; ok {
+				ctx.report(fmt.Sprintf(`There can be only one argument named "%s".`, arg.Name()), prev, arg)
+				continue
+			}
+			seen[arg.Name()] = arg
+		}
+	}
+
+	h := &visitor.Handler{
+		EnterSelectionField: func(_ context.Context, v model.SelectionField) error {
+			check(v.Arguments())
+			return nil
+		},
+	}
+	return withDirectives(h, func(d model.Directive, _ model.DirectiveLocation) {
+		check(d.Arguments())
+	})
+}
+
+// providedRequiredArguments checks that all arguments of non-null type
+// that do not have a default value are given to fields and directives
+func providedRequiredArguments(ctx *validationCtx) *visitor.Handler {
+	h := &visitor.Handler{
+		EnterSelectionField: func(_ context.Context, v model.SelectionField) error {
+			fdef := ctx.fieldDef()
+			if fdef == nil {
+				return nil
+			}
+
+			provided := make(map[string]struct{})
+			for arg := range v.Arguments() {
+				provided[arg.Name()] = struct{}{}
+			}
+
+			for adef := range fdef.Arguments() {
+				if adef.HasDefaultValue() || model.IsNullable(adef.Type()) {
+					continue
+				}
+				if _, ok := provided[adef.Name()]; !ok {
+					ctx.reportf(v, `Field "%s" argument "%s" of type "%s" is required, but it was not provided.`, v.Name(), adef.Name(), model.TypeString(adef.Type()))
+				}
+			}
+			return nil
+		},
+	}
+
+	return withDirectives(withTypeInfo(ctx, h), func(d model.Directive, _ model.DirectiveLocation) {
+		def, ok := ctx.lookupDirective(d.Name())
+		if !ok {
+			return
+		}
+
+		provided := make(map[string]struct{})
+		for arg := range d.Arguments() {
+			provided[arg.Name()] = struct{}{}
+		}
+
+		for adef := range def.Arguments() {
+			if adef.HasDefaultValue() || model.IsNullable(adef.Type()) {
+				continue
+			}
+			if _, ok := provided[adef.Name()]; !ok {
+				ctx.reportf(d, `Directive "@%s" argument "%s" of type "%s" is required, but it was not provided.`, d.Name(), adef.Name(), model.TypeString(adef.Type()))
+			}
+		}
+	})
+}
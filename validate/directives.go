@@ -0,0 +1,56 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+)
+
+// lookupDirective returns the definition of the directive `name`,
+// which is either defined in the schema or built-in
+func (ctx *validationCtx) lookupDirective(name string) (model.DirectiveDefinition, bool) {
+	if def, ok := ctx.schema.LookupDirective(name); ok {
+		return def, true
+	}
+	return model.LookupBuiltinDirective(name)
+}
+
+// knownDirectives checks that each directive is defined, and that it
+// is used in one of the locations that it was defined for
+func knownDirectives(ctx *validationCtx) *visitor.Handler {
+	return withDirectives(&visitor.Handler{}, func(d model.Directive, location model.DirectiveLocation) {
+		def, ok := ctx.lookupDirective(d.Name())
+		if !ok {
+			ctx.reportf(d, `Unknown directive "@%s".`, d.Name())
+			return
+		}
+
+		for _, l := range def.Locations() {
+			if l == location {
+				return
+			}
+		}
+		ctx.reportf(d, `Directive "@%s" may not be used on %s.`, d.Name(), location)
+	})
+}
+
+// uniqueDirectivesPerLocation checks that directives which are not
+// repeatable are applied at most once to each node
+func uniqueDirectivesPerLocation(ctx *validationCtx) *visitor.Handler {
+	return withDirectiveLists(&visitor.Handler{}, func(list []model.Directive, _ model.DirectiveLocation) {
+		seen := make(map[string]model.Directive)
+		for _, d := range list {
+			def, ok := ctx.lookupDirective(d.Name())
+			if !ok || def.IsRepeatable() {
+				continue
+			}
+
+			if prev, ok := seen[d.Name()]; ok {
+				ctx.report(fmt.Sprintf(`The directive "@%s" can only be used once at this location.`, d.Name()), prev, d)
+				continue
+			}
+			seen[d.Name()] = d
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lestrrat/go-graphql/model"
+)
+
+// Error describes a single problem found while validating a document
+type Error struct {
+	// Rule is the name of the validation rule that failed, as used in
+	// the GraphQL specification (e.g. "FieldsOnCorrectType")
+	Rule string
+
+	Message string
+
+	// Locations are the positions in the source document of the
+	// nodes involved. Nodes that do not record their position in the
+	// source are not included
+	Locations []Location
+
+	// Path is the list of response keys leading to the field where
+	// the problem was found, starting from the enclosing operation or
+	// fragment definition
+	Path []interface{}
+}
+
+// Location is a position in the source document
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Errors is the list of problems found while validating a document
+type Errors []*Error
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// MarshalJSON serializes the error in the format that GraphQL errors
+// are reported to the clients. The rule name is reported as an extension
+func (e *Error) MarshalJSON() ([]byte, error) {
+	var v struct {
+		Message    string                 `json:"message"`
+		Locations  []Location             `json:"locations,omitempty"`
+		Path       []interface{}          `json:"path,omitempty"`
+		Extensions map[string]interface{} `json:"extensions,omitempty"`
+	}
+	v.Message = e.Message
+	v.Locations = e.Locations
+	v.Path = e.Path
+	if e.Rule != "" {
+		v.Extensions = map[string]interface{}{"rule": e.Rule}
+	}
+	return json.Marshal(v)
+}
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no validation errors"
+	case 1:
+		return e[0].Error()
+	}
+
+	var buf bytes.Buffer
+	for i, err := range e {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(err.Error())
+	}
+	return buf.String()
+}
+
+// report records a validation error for the rule currently being run.
+// `nodes` are the nodes in the document that are involved
+func (ctx *validationCtx) report(message string, nodes ...interface{}) {
+	var path []interface{}
+	if len(ctx.path) > 0 {
+		path = make([]interface{}, len(ctx.path))
+		copy(path, ctx.path)
+	}
+
+	var locations []Location
+	for _, node := range nodes {
+		l, ok := node.(model.Locator)
+		if !ok {
+			continue
+		}
+		// nodes that were not created by the parser have no location
+		if pos := l.Location().Start; pos.Line > 0 {
+			locations = append(locations, Location{Line: pos.Line, Column: pos.Column})
+		}
+	}
+
+	ctx.errors = append(ctx.errors, &Error{
+		Rule:      ctx.rule,
+		Message:   message,
+		Locations: locations,
+		Path:      path,
+	})
+}
+
+func (ctx *validationCtx) reportf(node interface{}, format string, args ...interface{}) {
+	ctx.report(fmt.Sprintf(format, args...), node)
+}
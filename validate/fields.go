@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// fieldsOnCorrectType checks that each field that is selected is
+// defined by the type in which it is selected
+func fieldsOnCorrectType(ctx *validationCtx) *visitor.Handler {
+	return withTypeInfo(ctx, &visitor.Handler{
+		EnterSelectionField: func(_ context.Context, v model.SelectionField) error {
+			parent := ctx.parentType()
+			if parent == nil || ctx.fieldDef() != nil {
+				return nil
+			}
+
+			if suggestion, ok := suggest(v.Name(), fieldNames(parent)); ok {
+				ctx.reportf(v, `Cannot query field "%s" on type "%s". Did you mean "%s"?`, v.Name(), parent.Name(), suggestion)
+				return nil
+			}
+			ctx.reportf(v, `Cannot query field "%s" on type "%s".`, v.Name(), parent.Name())
+			return nil
+		},
+	})
+}
+
+// scalarLeafs checks that fields of scalar and enum types do not have
+// a selection of subfields, and that fields of object, interface and
+// union types do
+func scalarLeafs(ctx *validationCtx) *visitor.Handler {
+	return withTypeInfo(ctx, &visitor.Handler{
+		EnterSelectionField: func(_ context.Context, v model.SelectionField) error {
+			fdef := ctx.fieldDef()
+			if fdef == nil {
+				return nil
+			}
+
+			hasSelections := len(v.Selections()) > 0
+			if isCompositeType(ctx.lookupType(fdef.Type())) {
+				if !hasSelections {
+					ctx.reportf(v, `Field "%s" of type "%s" must have a selection of subfields. Did you mean "%s { ... }"?`, v.Name(), model.TypeString(fdef.Type()), v.Name())
+				}
+				return nil
+			}
+
+			if hasSelections {
+				ctx.reportf(v, `Field "%s" must not have a selection since type "%s" has no subfields.`, v.Name(), model.TypeString(fdef.Type()))
+			}
+			return nil
+		},
+	})
+}
+
+// fieldNames returns the names of the fields defined in `def`
+func fieldNames(def model.Definition) []string {
+	var names []string
+	switch def.(type) {
+	case model.ObjectDefinition:
+		for f := range def.(model.ObjectDefinition).Fields() {
+			names = append(names, f.Name())
+		}
+	case model.InterfaceDefinition:
+		for f := range def.(model.InterfaceDefinition).Fields() {
+			names = append(names, f.Name())
+		}
+	}
+	return names
+}
@@ -0,0 +1,120 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// knownFragmentNames checks that each fragment spread refers to a
+// fragment defined in the document
+func knownFragmentNames(ctx *validationCtx) *visitor.Handler {
+	return &visitor.Handler{
+		EnterFragmentSpread: func(_ context.Context, v model.FragmentSpread) error {
+			if _, ok := ctx.doc.LookupFragment(v.Name()); !ok {
+				ctx.reportf(v, `Unknown fragment "%s".`, v.Name())
+			}
+			return nil
+		},
+	}
+}
+
+// noUnusedFragments checks that each fragment defined in the document
+// is used by at least one of the operations, directly or indirectly
+func noUnusedFragments(ctx *validationCtx) *visitor.Handler {
+	var operations []model.OperationDefinition
+	var fragments []model.FragmentDefinition
+	return &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			operations = append(operations, v)
+			return nil
+		},
+		EnterFragmentDefinition: func(_ context.Context, v model.FragmentDefinition) error {
+			fragments = append(fragments, v)
+			return nil
+		},
+		LeaveDocument: func(_ context.Context, _ model.Document) error {
+			used := make(map[string]struct{})
+			for _, op := range operations {
+				ctx.recursivelyReferencedFragments(op.Selections(), used)
+			}
+
+			for _, frag := range fragments {
+				if _, ok := used[frag.Name()]; !ok {
+					ctx.reportf(frag, `Fragment "%s" is never used.`, frag.Name())
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// noFragmentCycles checks that fragments do not spread themselves,
+// either directly or through other fragments
+func noFragmentCycles(ctx *validationCtx) *visitor.Handler {
+	// fragments that have already been checked for cycles
+	visited := make(map[string]struct{})
+
+	// the chain of spreads currently being followed, and the position
+	// in the chain at which each fragment was entered
+	var spreadPath []model.FragmentSpread
+	spreadPathIndex := make(map[string]int)
+
+	var detectCycle func(model.FragmentDefinition)
+	detectCycle = func(frag model.FragmentDefinition) {
+		if _, ok := visited[frag.Name()]; ok {
+			return
+		}
+		visited[frag.Name()] = struct{}{}
+
+		spreads := fragmentSpreads(frag.Selections())
+		if len(spreads) == 0 {
+			return
+		}
+
+		spreadPathIndex[frag.Name()] = len(spreadPath)
+		defer delete(spreadPathIndex, frag.Name())
+
+		for _, spread := range spreads {
+			spreadPath = append(spreadPath, spread)
+			if idx, ok := spreadPathIndex[spread.Name()]; ok {
+				cycle := spreadPath[idx:]
+				nodes := make([]interface{}, len(cycle))
+				for i, s := range cycle {
+					nodes[i] = s
+				}
+				ctx.report(cycleMessage(spread.Name(), cycle[:len(cycle)-1]), nodes...)
+			} else if next, ok := ctx.doc.LookupFragment(spread.Name()); ok {
+				detectCycle(next)
+			}
+			spreadPath = spreadPath[:len(spreadPath)-1]
+		}
+	}
+
+	return &visitor.Handler{
+		EnterFragmentDefinition: func(_ context.Context, v model.FragmentDefinition) error {
+			detectCycle(v)
+			return nil
+		},
+	}
+}
+
+func cycleMessage(name string, via []model.FragmentSpread) string {
+	if len(via) == 0 {
+		return fmt.Sprintf(`Cannot spread fragment "%s" within itself.`, name)
+	}
+
+	var buf bytes.Buffer
+	for i, spread := range via {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('"')
+		buf.WriteString(spread.Name())
+		buf.WriteByte('"')
+	}
+	return fmt.Sprintf(`Cannot spread fragment "%s" within itself via %s.`, name, buf.String())
+}
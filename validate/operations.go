@@ -0,0 +1,124 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// uniqueOperationNames checks that each named operation in the
+// document has a unique name
+func uniqueOperationNames(ctx *validationCtx) *visitor.Handler {
+	seen := make(map[string]model.OperationDefinition)
+	return &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			if !v.HasName() {
+				return nil
+			}
+
+			if prev, ok := seen[v.Name()]; ok {
+				ctx.report(fmt.Sprintf(`There can be only one operation named "%s".`, v.Name()), prev, v)
+				return nil
+			}
+			seen[v.Name()] = v
+			return nil
+		},
+	}
+}
+
+// loneAnonymousOperation checks that an anonymous operation is the
+// only operation in the document
+func loneAnonymousOperation(ctx *validationCtx) *visitor.Handler {
+	var count int
+	return &visitor.Handler{
+		EnterDocument: func(_ context.Context, v model.Document) error {
+			for def := range v.Definitions() {
+				if _, ok := def.(model.OperationDefinition); ok {
+					count++
+				}
+			}
+			return nil
+		},
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			if !v.HasName() && count > 1 {
+				ctx.report(`This anonymous operation must be the only defined operation.`, v)
+			}
+			return nil
+		},
+	}
+}
+
+// singleFieldSubscriptions checks that subscription operations select
+// exactly one root field, which must not be an introspection field
+func singleFieldSubscriptions(ctx *validationCtx) *visitor.Handler {
+	return &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			if v.OperationType() != model.OperationTypeSubscription {
+				return nil
+			}
+
+			subject := `Anonymous Subscription`
+			if v.HasName() {
+				subject = fmt.Sprintf(`Subscription "%s"`, v.Name())
+			}
+
+			var keys []string
+			fields := make(map[string][]model.SelectionField)
+			ctx.collectRootFields(v.Selections(), fields, &keys, make(map[string]struct{}))
+
+			if len(keys) > 1 {
+				var extra []interface{}
+				for _, key := range keys[1:] {
+					for _, f := range fields[key] {
+						extra = append(extra, f)
+					}
+				}
+				ctx.report(subject+` must select only one top level field.`, extra...)
+			}
+
+			for _, key := range keys {
+				for _, f := range fields[key] {
+					if strings.HasPrefix(f.Name(), "__") {
+						ctx.report(subject+` must not select an introspection top level field.`, f)
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// collectRootFields groups the fields in the selection set by their
+// response key, following fragment spreads and inline fragments.
+// `keys` receives the response keys in the order they first appear
+func (ctx *validationCtx) collectRootFields(ch chan model.Selection, fields map[string][]model.SelectionField, keys *[]string, seen map[string]struct{}) {
+	for sel := range ch {
+		switch selectionKind(sel) {
+		case fieldSelection:
+			f := sel.(model.SelectionField)
+			key := f.Name()
+			if f.HasAlias() {
+				key = f.Alias()
+			}
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], f)
+		case inlineFragmentSelection:
+			ctx.collectRootFields(sel.(model.InlineFragment).Selections(), fields, keys, seen)
+		case fragmentSpreadSelection:
+			name := sel.(model.FragmentSpread).Name()
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+
+			if frag, ok := ctx.doc.LookupFragment(name); ok {
+				ctx.collectRootFields(frag.Selections(), fields, keys, seen)
+			}
+		}
+	}
+}
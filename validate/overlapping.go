@@ -0,0 +1,494 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// fieldEntry is a field found while collecting the fields of a
+// selection set, along with the type in which it was selected and
+// its definition (which may be nil if the field is not known)
+type fieldEntry struct {
+	parentType model.Definition
+	field      model.SelectionField
+	def        fieldDefinition
+}
+
+// fieldMap holds the fields of a selection set, grouped by their
+// response names, in the order that they appear
+type fieldMap struct {
+	keys   []string
+	fields map[string][]fieldEntry
+}
+
+func (m *fieldMap) add(name string, entry fieldEntry) {
+	if _, ok := m.fields[name]; !ok {
+		m.keys = append(m.keys, name)
+	}
+	m.fields[name] = append(m.fields[name], entry)
+}
+
+type fieldsAndFragmentNames struct {
+	fields        *fieldMap
+	fragmentNames []string
+}
+
+// fragmentPairSet remembers the pairs of fragments that have been
+// compared, and whether they were compared as mutually exclusive
+type fragmentPairSet map[[2]string]bool
+
+func (s fragmentPairSet) has(a, b string, mutuallyExclusive bool) bool {
+	if a > b {
+		a, b = b, a
+	}
+	exclusive, ok := s[[2]string{a, b}]
+	if !ok {
+		return false
+	}
+
+	// A pair that was compared as mutually exclusive must be compared
+	// again if it is now being compared as not mutually exclusive,
+	// as the latter checks for more conflicts
+	if !mutuallyExclusive {
+		return !exclusive
+	}
+	return true
+}
+
+func (s fragmentPairSet) add(a, b string, mutuallyExclusive bool) {
+	if a > b {
+		a, b = b, a
+	}
+	s[[2]string{a, b}] = mutuallyExclusive
+}
+
+// conflict describes why two fields with the same response name can
+// not be merged. Either `message` or `subconflicts` is populated.
+// `fields` holds all of the fields involved, including the subfields
+type conflict struct {
+	responseName string
+	message      string
+	subconflicts []*conflict
+	fields       []interface{}
+}
+
+func (c *conflict) reason() string {
+	if len(c.subconflicts) == 0 {
+		return c.message
+	}
+
+	var buf bytes.Buffer
+	for i, sub := range c.subconflicts {
+		if i > 0 {
+			buf.WriteString(" and ")
+		}
+		fmt.Fprintf(&buf, `subfields "%s" conflict because %s`, sub.responseName, sub.reason())
+	}
+	return buf.String()
+}
+
+func (c *conflict) Error() string {
+	return fmt.Sprintf(`Fields "%s" conflict because %s. Use different aliases on the fields to fetch both if this was intentional.`, c.responseName, c.reason())
+}
+
+type overlapCtx struct {
+	*validationCtx
+
+	// cache of collected fields, keyed by the node that owns the
+	// selection set. Fragments are keyed by their definitions
+	cache map[interface{}]*fieldsAndFragmentNames
+
+	comparedFragmentPairs fragmentPairSet
+}
+
+// overlappingFieldsCanBeMerged checks that all fields with the same
+// response name in a selection set, including those reached through
+// fragments, can be merged into a single field in the response
+func overlappingFieldsCanBeMerged(ctx *validationCtx) *visitor.Handler {
+	octx := &overlapCtx{
+		validationCtx:         ctx,
+		cache:                 make(map[interface{}]*fieldsAndFragmentNames),
+		comparedFragmentPairs: make(fragmentPairSet),
+	}
+
+	check := func(parentType model.Definition, container model.SelectionsContainer) error {
+		for _, c := range octx.findConflictsWithinSelectionSet(parentType, container) {
+			ctx.report(c.Error(), c.fields...)
+		}
+		return nil
+	}
+
+	return withTypeInfo(ctx, &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			return check(ctx.parentType(), v)
+		},
+		EnterFragmentDefinition: func(_ context.Context, v model.FragmentDefinition) error {
+			return check(ctx.parentType(), v)
+		},
+		EnterSelectionField: func(_ context.Context, v model.SelectionField) error {
+			var typ model.Definition
+			if fdef := ctx.fieldDef(); fdef != nil {
+				typ = ctx.lookupType(fdef.Type())
+			}
+			return check(typ, v)
+		},
+		EnterInlineFragment: func(_ context.Context, v model.InlineFragment) error {
+			typ := ctx.parentType()
+			if cond := v.TypeCondition(); cond != nil {
+				typ = ctx.lookupType(cond)
+			}
+			return check(typ, v)
+		},
+	})
+}
+
+func (ctx *overlapCtx) findConflictsWithinSelectionSet(parentType model.Definition, container model.SelectionsContainer) []*conflict {
+	var conflicts []*conflict
+
+	collected := ctx.fieldsAndFragmentNames(parentType, container)
+	conflicts = ctx.collectConflictsWithin(conflicts, collected.fields)
+
+	for i, name := range collected.fragmentNames {
+		conflicts = ctx.collectConflictsBetweenFieldsAndFragment(conflicts, make(map[string]struct{}), false, collected.fields, name)
+		for _, other := range collected.fragmentNames[i+1:] {
+			conflicts = ctx.collectConflictsBetweenFragments(conflicts, false, name, other)
+		}
+	}
+	return conflicts
+}
+
+// collectConflictsBetweenFieldsAndFragment compares the fields in
+// `fields` against the fields in the fragment `name`, as well as the
+// fragments that it spreads. `visited` protects against fragment cycles
+func (ctx *overlapCtx) collectConflictsBetweenFieldsAndFragment(conflicts []*conflict, visited map[string]struct{}, mutuallyExclusive bool, fields *fieldMap, name string) []*conflict {
+	if _, ok := visited[name]; ok {
+		return conflicts
+	}
+	visited[name] = struct{}{}
+
+	frag, ok := ctx.doc.LookupFragment(name)
+	if !ok {
+		return conflicts
+	}
+
+	collected := ctx.referencedFieldsAndFragmentNames(frag)
+	if fields == collected.fields {
+		return conflicts
+	}
+
+	conflicts = ctx.collectConflictsBetween(conflicts, mutuallyExclusive, fields, collected.fields)
+	for _, other := range collected.fragmentNames {
+		conflicts = ctx.collectConflictsBetweenFieldsAndFragment(conflicts, visited, mutuallyExclusive, fields, other)
+	}
+	return conflicts
+}
+
+// collectConflictsBetweenFragments compares the fields of fragments
+// `name1` and `name2`, as well as the fragments that they spread
+func (ctx *overlapCtx) collectConflictsBetweenFragments(conflicts []*conflict, mutuallyExclusive bool, name1, name2 string) []*conflict {
+	if name1 == name2 {
+		return conflicts
+	}
+
+	if ctx.comparedFragmentPairs.has(name1, name2, mutuallyExclusive) {
+		return conflicts
+	}
+	ctx.comparedFragmentPairs.add(name1, name2, mutuallyExclusive)
+
+	frag1, ok := ctx.doc.LookupFragment(name1)
+	if !ok {
+		return conflicts
+	}
+	frag2, ok := ctx.doc.LookupFragment(name2)
+	if !ok {
+		return conflicts
+	}
+
+	collected1 := ctx.referencedFieldsAndFragmentNames(frag1)
+	collected2 := ctx.referencedFieldsAndFragmentNames(frag2)
+
+	conflicts = ctx.collectConflictsBetween(conflicts, mutuallyExclusive, collected1.fields, collected2.fields)
+	for _, other := range collected2.fragmentNames {
+		conflicts = ctx.collectConflictsBetweenFragments(conflicts, mutuallyExclusive, name1, other)
+	}
+	for _, other := range collected1.fragmentNames {
+		conflicts = ctx.collectConflictsBetweenFragments(conflicts, mutuallyExclusive, other, name2)
+	}
+	return conflicts
+}
+
+// findConflictsBetweenSubSelectionSets compares the selection sets of
+// two fields that share the same response name
+func (ctx *overlapCtx) findConflictsBetweenSubSelectionSets(mutuallyExclusive bool, parentType1 model.Definition, container1 model.SelectionsContainer, parentType2 model.Definition, container2 model.SelectionsContainer) []*conflict {
+	var conflicts []*conflict
+
+	collected1 := ctx.fieldsAndFragmentNames(parentType1, container1)
+	collected2 := ctx.fieldsAndFragmentNames(parentType2, container2)
+
+	conflicts = ctx.collectConflictsBetween(conflicts, mutuallyExclusive, collected1.fields, collected2.fields)
+	for _, name := range collected2.fragmentNames {
+		conflicts = ctx.collectConflictsBetweenFieldsAndFragment(conflicts, make(map[string]struct{}), mutuallyExclusive, collected1.fields, name)
+	}
+	for _, name := range collected1.fragmentNames {
+		conflicts = ctx.collectConflictsBetweenFieldsAndFragment(conflicts, make(map[string]struct{}), mutuallyExclusive, collected2.fields, name)
+	}
+	for _, name1 := range collected1.fragmentNames {
+		for _, name2 := range collected2.fragmentNames {
+			conflicts = ctx.collectConflictsBetweenFragments(conflicts, mutuallyExclusive, name1, name2)
+		}
+	}
+	return conflicts
+}
+
+// collectConflictsWithin compares all pairs of fields with the same
+// response name within a single selection set
+func (ctx *overlapCtx) collectConflictsWithin(conflicts []*conflict, fields *fieldMap) []*conflict {
+	for _, responseName := range fields.keys {
+		entries := fields.fields[responseName]
+		for i := range entries {
+			for j := i + 1; j < len(entries); j++ {
+				if c := ctx.findConflict(false, responseName, entries[i], entries[j]); c != nil {
+					conflicts = append(conflicts, c)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// collectConflictsBetween compares the fields in `fields1` against
+// the fields with the same response names in `fields2`
+func (ctx *overlapCtx) collectConflictsBetween(conflicts []*conflict, mutuallyExclusive bool, fields1, fields2 *fieldMap) []*conflict {
+	for _, responseName := range fields1.keys {
+		entries2, ok := fields2.fields[responseName]
+		if !ok {
+			continue
+		}
+
+		for _, entry1 := range fields1.fields[responseName] {
+			for _, entry2 := range entries2 {
+				if c := ctx.findConflict(mutuallyExclusive, responseName, entry1, entry2); c != nil {
+					conflicts = append(conflicts, c)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// findConflict determines if the two fields with the same response
+// name can be merged. Returns nil if they can
+func (ctx *overlapCtx) findConflict(parentFieldsAreMutuallyExclusive bool, responseName string, entry1, entry2 fieldEntry) *conflict {
+	// Fields that are selected on two different object types can never
+	// be part of the same response object, so their names and arguments
+	// may differ. Their types must still match, though
+	_, isObject1 := entry1.parentType.(model.ObjectDefinition)
+	_, isObject2 := entry2.parentType.(model.ObjectDefinition)
+	mutuallyExclusive := parentFieldsAreMutuallyExclusive ||
+		(entry1.parentType != entry2.parentType && isObject1 && isObject2)
+
+	if !mutuallyExclusive {
+		if name1, name2 := entry1.field.Name(), entry2.field.Name(); name1 != name2 {
+			return &conflict{
+				responseName: responseName,
+				message:      fmt.Sprintf(`"%s" and "%s" are different fields`, name1, name2),
+				fields:       []interface{}{entry1.field, entry2.field},
+			}
+		}
+
+		if !sameArguments(entry1.field.Arguments(), entry2.field.Arguments()) {
+			return &conflict{
+				responseName: responseName,
+				message:      `they have differing arguments`,
+				fields:       []interface{}{entry1.field, entry2.field},
+			}
+		}
+	}
+
+	var type1, type2 model.Type
+	if entry1.def != nil {
+		type1 = entry1.def.Type()
+	}
+	if entry2.def != nil {
+		type2 = entry2.def.Type()
+	}
+
+	if type1 != nil && type2 != nil && ctx.doTypesConflict(type1, type2) {
+		return &conflict{
+			responseName: responseName,
+			message:      fmt.Sprintf(`they return conflicting types "%s" and "%s"`, model.TypeString(type1), model.TypeString(type2)),
+			fields:       []interface{}{entry1.field, entry2.field},
+		}
+	}
+
+	if len(entry1.field.Selections()) == 0 || len(entry2.field.Selections()) == 0 {
+		return nil
+	}
+
+	var subType1, subType2 model.Definition
+	if type1 != nil {
+		subType1 = ctx.lookupType(type1)
+	}
+	if type2 != nil {
+		subType2 = ctx.lookupType(type2)
+	}
+
+	subconflicts := ctx.findConflictsBetweenSubSelectionSets(mutuallyExclusive, subType1, entry1.field, subType2, entry2.field)
+	if len(subconflicts) == 0 {
+		return nil
+	}
+	fields := []interface{}{entry1.field, entry2.field}
+	for _, sub := range subconflicts {
+		fields = append(fields, sub.fields...)
+	}
+	return &conflict{
+		responseName: responseName,
+		subconflicts: subconflicts,
+		fields:       fields,
+	}
+}
+
+// doTypesConflict returns true if the two types can not be merged
+// into a single value in the response
+func (ctx *overlapCtx) doTypesConflict(type1, type2 model.Type) bool {
+	if model.IsNullable(type1) != model.IsNullable(type2) {
+		return true
+	}
+
+	list1, isList1 := type1.(model.ListType)
+	list2, isList2 := type2.(model.ListType)
+	if isList1 || isList2 {
+		if isList1 && isList2 {
+			return ctx.doTypesConflict(list1.Type(), list2.Type())
+		}
+		return true
+	}
+
+	if !isCompositeType(ctx.lookupType(type1)) || !isCompositeType(ctx.lookupType(type2)) {
+		return model.TypeString(type1) != model.TypeString(type2)
+	}
+	return false
+}
+
+// fieldsAndFragmentNames returns the fields and the names of the
+// fragments spread in the selection set of `container`. The result is
+// cached, as the same selection sets are compared many times
+func (ctx *overlapCtx) fieldsAndFragmentNames(parentType model.Definition, container model.SelectionsContainer) *fieldsAndFragmentNames {
+	if cached, ok := ctx.cache[container]; ok {
+		return cached
+	}
+
+	collected := &fieldsAndFragmentNames{
+		fields: &fieldMap{fields: make(map[string][]fieldEntry)},
+	}
+	ctx.collectFieldsAndFragmentNames(collected, make(map[string]struct{}), parentType, container.Selections())
+	ctx.cache[container] = collected
+	return collected
+}
+
+func (ctx *overlapCtx) referencedFieldsAndFragmentNames(frag model.FragmentDefinition) *fieldsAndFragmentNames {
+	return ctx.fieldsAndFragmentNames(ctx.lookupType(frag.Type()), frag)
+}
+
+func (ctx *overlapCtx) collectFieldsAndFragmentNames(collected *fieldsAndFragmentNames, seen map[string]struct{}, parentType model.Definition, ch chan model.Selection) {
+	for sel := range ch {
+		switch selectionKind(sel) {
+		case fieldSelection:
+			field := sel.(model.SelectionField)
+			responseName := field.Name()
+			if field.HasAlias() {
+				responseName = field.Alias()
+			}
+
+			var def fieldDefinition
+			if isCompositeType(parentType) {
+				def = ctx.lookupFieldDefinition(parentType, field.Name())
+			}
+			collected.fields.add(responseName, fieldEntry{
+				parentType: parentType,
+				field:      field,
+				def:        def,
+			})
+		case inlineFragmentSelection:
+			frag := sel.(model.InlineFragment)
+			typ := parentType
+			if cond := frag.TypeCondition(); cond != nil {
+				typ = ctx.lookupType(cond)
+			}
+			ctx.collectFieldsAndFragmentNames(collected, seen, typ, frag.Selections())
+		case fragmentSpreadSelection:
+			name := sel.(model.FragmentSpread).Name()
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			collected.fragmentNames = append(collected.fragmentNames, name)
+		}
+	}
+}
+
+func sameArguments(ch1, ch2 chan model.Argument) bool {
+	if len(ch1) != len(ch2) {
+		return false
+	}
+
+	args2 := make(map[string]model.Value)
+	for arg := range ch2 {
+		args2[arg.Name()] = arg.Value()
+	}
+
+	for arg := range ch1 {
+		v, ok := args2[arg.Name()]
+		if !ok || !sameValue(arg.Value(), v) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameValue(v1, v2 model.Value) bool {
+	if v1.Kind() != v2.Kind() {
+		return false
+	}
+
+	switch v1.Kind() {
+	case model.ObjectKind:
+		// compared field by field below
+	case model.ListKind:
+		values1 := v1.(model.ListValue).Values()
+		values2 := v2.(model.ListValue).Values()
+		if len(values1) != len(values2) {
+			return false
+		}
+		for elem := range values1 {
+			if !sameValue(elem, <-values2) {
+				return false
+			}
+		}
+		return true
+	default:
+		return v1.Value() == v2.Value()
+	}
+
+	fields1 := v1.(model.ObjectValue).Fields()
+	fields2 := v2.(model.ObjectValue).Fields()
+	if len(fields1) != len(fields2) {
+		return false
+	}
+
+	values2 := make(map[string]model.Value)
+	for f := range fields2 {
+		values2[f.Name()] = f.Value()
+	}
+	for f := range fields1 {
+		v, ok := values2[f.Name()]
+		if !ok || !sameValue(f.Value(), v) {
+			return false
+		}
+	}
+	return true
+}
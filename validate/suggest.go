@@ -0,0 +1,79 @@
+package validate
+
+import "strings"
+
+// suggest returns the option that is closest to `input`, for use in
+// "did you mean ...?" messages. Options that are too different from
+// `input` are never suggested
+func suggest(input string, options []string) (string, bool) {
+	threshold := len(input)*4/10 + 1
+
+	var found bool
+	var best string
+	var bestDistance int
+	for _, option := range options {
+		d := lexicalDistance(input, option)
+		if d > threshold {
+			continue
+		}
+
+		if !found || d < bestDistance || (d == bestDistance && option < best) {
+			found = true
+			best = option
+			bestDistance = d
+		}
+	}
+	return best, found
+}
+
+// lexicalDistance computes the optimal string alignment distance
+// between `a` and `b`: the number of insertions, deletions,
+// substitutions and transpositions required to turn one into the
+// other. A difference only in case counts as a single edit
+func lexicalDistance(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	la := strings.ToLower(a)
+	lb := strings.ToLower(b)
+	if la == lb {
+		return 1
+	}
+
+	s := []rune(la)
+	t := []rune(lb)
+
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			v := d[i-1][j] + 1
+			if x := d[i][j-1] + 1; x < v {
+				v = x
+			}
+			if x := d[i-1][j-1] + cost; x < v {
+				v = x
+			}
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				if x := d[i-2][j-2] + cost; x < v {
+					v = x
+				}
+			}
+			d[i][j] = v
+		}
+	}
+	return d[len(s)][len(t)]
+}
@@ -0,0 +1,259 @@
+package validate
+
+import (
+	"github.com/lestrrat/go-graphql/introspection"
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// fieldDefinition is the common interface for fields defined in
+// objects (model.ObjectFieldDefinition) and interfaces
+// (model.InterfaceFieldDefinition)
+type fieldDefinition interface {
+	model.Namer
+	Type() model.Type
+	Arguments() chan model.ObjectFieldArgumentDefinition
+}
+
+// parentType returns the composite type in which the selections
+// currently being visited are evaluated. Returns nil if the type
+// is not known
+func (ctx *validationCtx) parentType() model.Definition {
+	if l := len(ctx.types); l > 0 {
+		return ctx.types[l-1]
+	}
+	return nil
+}
+
+// fieldDef returns the definition of the field currently being
+// visited. Returns nil if the field is not known
+func (ctx *validationCtx) fieldDef() fieldDefinition {
+	if l := len(ctx.fields); l > 0 {
+		return ctx.fields[l-1]
+	}
+	return nil
+}
+
+// pushType records `def` as the current parent type. Types that can
+// not have a selection of subfields are recorded as unknown (nil)
+func (ctx *validationCtx) pushType(def model.Definition) {
+	if !isCompositeType(def) {
+		def = nil
+	}
+	ctx.types = append(ctx.types, def)
+}
+
+func (ctx *validationCtx) popType() {
+	ctx.types = ctx.types[:len(ctx.types)-1]
+}
+
+// lookupType returns the definition of the named type at the bottom
+// of `t`. Returns nil if the type is neither defined in the schema nor
+// a built-in scalar
+func (ctx *validationCtx) lookupType(t model.Type) model.Definition {
+	for {
+		lt, ok := t.(model.ListType)
+		if !ok {
+			break
+		}
+		t = lt.Type()
+	}
+
+	n, ok := t.(model.Namer)
+	if !ok {
+		return nil
+	}
+
+	if def, ok := introspection.LookupType(n.Name()); ok {
+		return def
+	}
+
+	if def, ok := ctx.schema.LookupType(n.Name()); ok {
+		return def
+	}
+
+	if def, ok := model.LookupBuiltinScalar(n.Name()); ok {
+		return def
+	}
+	return nil
+}
+
+// rootType returns the root type for operations of type `typ`.
+// Returns nil if the schema does not support such operations
+func (ctx *validationCtx) rootType(typ model.OperationType) model.Definition {
+	var name string
+	switch typ {
+	case model.OperationTypeQuery:
+		name = "Query"
+	case model.OperationTypeMutation:
+		name = "Mutation"
+	case model.OperationTypeSubscription:
+		name = "Subscription"
+	default:
+		return nil
+	}
+
+	if s, ok := ctx.schema.LookupSchema(); ok {
+		var t model.NamedType
+		switch typ {
+		case model.OperationTypeQuery:
+			t = s.Query()
+		case model.OperationTypeMutation:
+			t = s.Mutation()
+		case model.OperationTypeSubscription:
+			t = s.Subscription()
+		}
+		if t == nil {
+			return nil
+		}
+		name = t.Name()
+	}
+	return ctx.lookupType(model.NewNamedType(name))
+}
+
+// lookupFieldDefinition returns the definition of the field `name` in
+// the composite type `parent`. Returns nil if there is no such field.
+// The __typename meta field is available in all composite types, and
+// the __schema and __type meta fields are available in the query root
+func (ctx *validationCtx) lookupFieldDefinition(parent model.Definition, name string) fieldDefinition {
+	switch name {
+	case introspection.TypeNameField.Name():
+		if isCompositeType(parent) {
+			return introspection.TypeNameField
+		}
+	case introspection.SchemaField.Name(), introspection.TypeField.Name():
+		if parent == ctx.rootType(model.OperationTypeQuery) {
+			if name == introspection.SchemaField.Name() {
+				return introspection.SchemaField
+			}
+			return introspection.TypeField
+		}
+	}
+
+	switch parent.(type) {
+	case model.ObjectDefinition:
+		for f := range parent.(model.ObjectDefinition).Fields() {
+			if f.Name() == name {
+				return f
+			}
+		}
+	case model.InterfaceDefinition:
+		for f := range parent.(model.InterfaceDefinition).Fields() {
+			if f.Name() == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// withTypeInfo makes `h` keep track of the parent type and the field
+// definition for each node being visited, so that the handlers in `h`
+// can query them through ctx.parentType() and ctx.fieldDef().
+//
+// The handlers for fields and inline fragments are called while the
+// parent type is still the type that contains the node
+func withTypeInfo(ctx *validationCtx, h *visitor.Handler) *visitor.Handler {
+	enterOperationDefinition := h.EnterOperationDefinition
+	h.EnterOperationDefinition = func(c context.Context, v model.OperationDefinition) error {
+		ctx.pushType(ctx.rootType(v.OperationType()))
+		if enterOperationDefinition != nil {
+			return enterOperationDefinition(c, v)
+		}
+		return nil
+	}
+
+	leaveOperationDefinition := h.LeaveOperationDefinition
+	h.LeaveOperationDefinition = func(c context.Context, v model.OperationDefinition) error {
+		defer ctx.popType()
+		if leaveOperationDefinition != nil {
+			return leaveOperationDefinition(c, v)
+		}
+		return nil
+	}
+
+	enterFragmentDefinition := h.EnterFragmentDefinition
+	h.EnterFragmentDefinition = func(c context.Context, v model.FragmentDefinition) error {
+		ctx.pushType(ctx.lookupType(v.Type()))
+		if enterFragmentDefinition != nil {
+			return enterFragmentDefinition(c, v)
+		}
+		return nil
+	}
+
+	leaveFragmentDefinition := h.LeaveFragmentDefinition
+	h.LeaveFragmentDefinition = func(c context.Context, v model.FragmentDefinition) error {
+		defer ctx.popType()
+		if leaveFragmentDefinition != nil {
+			return leaveFragmentDefinition(c, v)
+		}
+		return nil
+	}
+
+	enterSelectionField := h.EnterSelectionField
+	h.EnterSelectionField = func(c context.Context, v model.SelectionField) error {
+		var fdef fieldDefinition
+		if parent := ctx.parentType(); parent != nil {
+			fdef = ctx.lookupFieldDefinition(parent, v.Name())
+		}
+		ctx.fields = append(ctx.fields, fdef)
+
+		var err error
+		if enterSelectionField != nil {
+			err = enterSelectionField(c, v)
+		}
+
+		var typ model.Definition
+		if fdef != nil {
+			typ = ctx.lookupType(fdef.Type())
+		}
+		ctx.pushType(typ)
+		return err
+	}
+
+	leaveSelectionField := h.LeaveSelectionField
+	h.LeaveSelectionField = func(c context.Context, v model.SelectionField) error {
+		ctx.popType()
+		defer func() { ctx.fields = ctx.fields[:len(ctx.fields)-1] }()
+		if leaveSelectionField != nil {
+			return leaveSelectionField(c, v)
+		}
+		return nil
+	}
+
+	enterInlineFragment := h.EnterInlineFragment
+	h.EnterInlineFragment = func(c context.Context, v model.InlineFragment) error {
+		var err error
+		if enterInlineFragment != nil {
+			err = enterInlineFragment(c, v)
+		}
+
+		typ := ctx.parentType()
+		if cond := v.TypeCondition(); cond != nil {
+			typ = ctx.lookupType(cond)
+		}
+		ctx.pushType(typ)
+		return err
+	}
+
+	leaveInlineFragment := h.LeaveInlineFragment
+	h.LeaveInlineFragment = func(c context.Context, v model.InlineFragment) error {
+		ctx.popType()
+		if leaveInlineFragment != nil {
+			return leaveInlineFragment(c, v)
+		}
+		return nil
+	}
+	return h
+}
+
+// isCompositeType returns true if `def` is an object, interface or
+// union type, i.e. a type that requires a selection of subfields
+func isCompositeType(def model.Definition) bool {
+	switch def.(type) {
+	case model.ObjectDefinition, model.InterfaceDefinition, model.UnionDefinition:
+		return true
+	}
+	return false
+}
@@ -1,60 +1,307 @@
+// Package validate implements the validation rules for executable
+// GraphQL documents, as described in the GraphQL specification, along
+// with checks on the schema documents that they are validated against
 package validate
 
 import (
-	"errors"
-
 	"github.com/lestrrat/go-graphql/model"
 	"github.com/lestrrat/go-graphql/visitor"
+	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 )
 
-var h = &visitor.Handler{
-	EnterFragmentSpread:     enterFragmentSpread,
-	EnterFragmentDefinition: enterFragmentDefinition,
-	LeaveFragmentDefinition: leaveFragmentDefinition,
+// rule creates the visitor.Handler that checks a single validation
+// rule. Each rule is run as a separate pass over the document, may
+// keep its own state in the handler closures, and reports the problems
+// that it finds through validationCtx.report
+type rule func(*validationCtx) *visitor.Handler
+
+var rules = []struct {
+	name string
+	rule rule
+}{
+	{"UniqueOperationNames", uniqueOperationNames},
+	{"LoneAnonymousOperation", loneAnonymousOperation},
+	{"SingleFieldSubscriptions", singleFieldSubscriptions},
+	{"KnownFragmentNames", knownFragmentNames},
+	{"NoUnusedFragments", noUnusedFragments},
+	{"NoFragmentCycles", noFragmentCycles},
+	{"KnownArgumentNames", knownArgumentNames},
+	{"UniqueArgumentNames", uniqueArgumentNames},
+	{"ProvidedRequiredArguments", providedRequiredArguments},
+	{"UniqueVariableNames", uniqueVariableNames},
+	{"NoUndefinedVariables", noUndefinedVariables},
+	{"NoUnusedVariables", noUnusedVariables},
+	{"KnownDirectives", knownDirectives},
+	{"UniqueDirectivesPerLocation", uniqueDirectivesPerLocation},
+	{"FieldsOnCorrectType", fieldsOnCorrectType},
+	{"ScalarLeafs", scalarLeafs},
+	{"OverlappingFieldsCanBeMerged", overlappingFieldsCanBeMerged},
+}
+
+// schemaRules are the rules checked by ValidateSchema
+var schemaRules = []struct {
+	name string
+	rule rule
+}{
+	{"DefaultValuesOfCorrectType", defaultValuesOfCorrectType},
 }
 
 type validationCtx struct {
 	context.Context
 
-	container interface{} // Current top level container
-	schema    model.Document
+	schema model.Document
+	doc    model.Document
+
+	rule   string        // name of the rule currently being run
+	path   []interface{} // response keys leading to the current field
+	errors Errors
+
+	types  []model.Definition // stack of parent types (see typeinfo.go)
+	fields []fieldDefinition  // stack of field definitions (see typeinfo.go)
 }
 
+// Validate checks that `doc` is valid against `schema`. All rules are
+// checked, even after a problem has been found. If there are any
+// problems, the returned error is of type Errors.
 func Validate(c context.Context, schema, doc model.Document) error {
 	var ctx validationCtx
 	ctx.Context = c
 	ctx.schema = schema
-	return visitor.Visit(&ctx, h, doc)
-}
+	ctx.doc = doc
+
+	for _, r := range rules {
+		ctx.rule = r.name
+		if err := visitor.Visit(&ctx, withPath(&ctx, r.rule(&ctx)), doc); err != nil {
+			return errors.Wrapf(err, `failed to run validation rule %s`, r.name)
+		}
+	}
 
-func enterFragmentDefinition(c context.Context, v model.FragmentDefinition) error {
-	ctx := c.(*validationCtx)
-	ctx.container = v
+	if len(ctx.errors) > 0 {
+		return ctx.errors
+	}
 	return nil
 }
 
-func leaveFragmentDefinition(c context.Context, v model.FragmentDefinition) error {
-	ctx := c.(*validationCtx)
-	if x, ok := ctx.container.(model.FragmentDefinition); ok {
-		if x.Name() == v.Name() {
-			ctx.container = nil
+// ValidateSchema checks that the definitions in `schema` are
+// consistent, e.g. that default values match the types that they are
+// declared with. If there are any problems, the returned error is of
+// type Errors.
+func ValidateSchema(c context.Context, schema model.Document) error {
+	var ctx validationCtx
+	ctx.Context = c
+	ctx.schema = schema
+	ctx.doc = schema
+
+	for _, r := range schemaRules {
+		ctx.rule = r.name
+		if err := visitor.Visit(&ctx, r.rule(&ctx), schema); err != nil {
+			return errors.Wrapf(err, `failed to run validation rule %s`, r.name)
 		}
 	}
+
+	if len(ctx.errors) > 0 {
+		return ctx.errors
+	}
 	return nil
 }
 
-func enterFragmentSpread(c context.Context, v model.FragmentSpread) error {
-	ctx := c.(*validationCtx)
-	if ctx.container == nil {
-		return errors.New(`fragment spread in top level`)
+// withPath makes `h` keep track of the response keys leading to the
+// field being visited, so that they can be reported along with errors
+func withPath(ctx *validationCtx, h *visitor.Handler) *visitor.Handler {
+	enterOperationDefinition := h.EnterOperationDefinition
+	h.EnterOperationDefinition = func(c context.Context, v model.OperationDefinition) error {
+		ctx.path = ctx.path[:0]
+		if enterOperationDefinition != nil {
+			return enterOperationDefinition(c, v)
+		}
+		return nil
 	}
 
-	f, ok := ctx.container.(model.FragmentDefinition)
-	if ok {
-		if f.Name() == v.Name() {
-			return errors.New(`can not spread fragment inside the same named fragment`)
+	enterFragmentDefinition := h.EnterFragmentDefinition
+	h.EnterFragmentDefinition = func(c context.Context, v model.FragmentDefinition) error {
+		ctx.path = ctx.path[:0]
+		if enterFragmentDefinition != nil {
+			return enterFragmentDefinition(c, v)
+		}
+		return nil
+	}
+
+	enterSelectionField := h.EnterSelectionField
+	h.EnterSelectionField = func(c context.Context, v model.SelectionField) error {
+		key := v.Name()
+		if v.HasAlias() {
+			key = v.Alias()
+		}
+		ctx.path = append(ctx.path, key)
+		if enterSelectionField != nil {
+			return enterSelectionField(c, v)
+		}
+		return nil
+	}
+
+	leaveSelectionField := h.LeaveSelectionField
+	h.LeaveSelectionField = func(c context.Context, v model.SelectionField) error {
+		defer func() { ctx.path = ctx.path[:len(ctx.path)-1] }()
+		if leaveSelectionField != nil {
+			return leaveSelectionField(c, v)
+		}
+		return nil
+	}
+	return h
+}
+
+// withDirectives makes `h` call `f` for every directive that appears
+// in the executable nodes of the document, along with its location.
+// Any handlers that are already set in `h` are called first
+func withDirectives(h *visitor.Handler, f func(model.Directive, model.DirectiveLocation)) *visitor.Handler {
+	return withDirectiveLists(h, func(list []model.Directive, location model.DirectiveLocation) {
+		for _, d := range list {
+			f(d, location)
+		}
+	})
+}
+
+// withDirectiveLists is like withDirectives, but calls `f` once for
+// each node with all of the directives that are applied to it
+func withDirectiveLists(h *visitor.Handler, f func([]model.Directive, model.DirectiveLocation)) *visitor.Handler {
+	each := func(ch chan model.Directive, location model.DirectiveLocation) error {
+		if len(ch) == 0 {
+			return nil
+		}
+
+		list := make([]model.Directive, 0, len(ch))
+		for d := range ch {
+			list = append(list, d)
+		}
+		f(list, location)
+		return nil
+	}
+
+	enterOperationDefinition := h.EnterOperationDefinition
+	h.EnterOperationDefinition = func(c context.Context, v model.OperationDefinition) error {
+		if enterOperationDefinition != nil {
+			if err := enterOperationDefinition(c, v); err != nil {
+				return err
+			}
+		}
+		location := model.DirectiveLocationQuery
+		switch v.OperationType() {
+		case model.OperationTypeMutation:
+			location = model.DirectiveLocationMutation
+		case model.OperationTypeSubscription:
+			location = model.DirectiveLocationSubscription
+		}
+		return each(v.Directives(), location)
+	}
+
+	enterVariableDefinition := h.EnterVariableDefinition
+	h.EnterVariableDefinition = func(c context.Context, v model.VariableDefinition) error {
+		if enterVariableDefinition != nil {
+			if err := enterVariableDefinition(c, v); err != nil {
+				return err
+			}
+		}
+		return each(v.Directives(), model.DirectiveLocationVariableDefinition)
+	}
+
+	enterFragmentDefinition := h.EnterFragmentDefinition
+	h.EnterFragmentDefinition = func(c context.Context, v model.FragmentDefinition) error {
+		if enterFragmentDefinition != nil {
+			if err := enterFragmentDefinition(c, v); err != nil {
+				return err
+			}
+		}
+		return each(v.Directives(), model.DirectiveLocationFragmentDefinition)
+	}
+
+	enterSelectionField := h.EnterSelectionField
+	h.EnterSelectionField = func(c context.Context, v model.SelectionField) error {
+		if enterSelectionField != nil {
+			if err := enterSelectionField(c, v); err != nil {
+				return err
+			}
+		}
+		return each(v.Directives(), model.DirectiveLocationField)
+	}
+
+	enterFragmentSpread := h.EnterFragmentSpread
+	h.EnterFragmentSpread = func(c context.Context, v model.FragmentSpread) error {
+		if enterFragmentSpread != nil {
+			if err := enterFragmentSpread(c, v); err != nil {
+				return err
+			}
+		}
+		return each(v.Directives(), model.DirectiveLocationFragmentSpread)
+	}
+
+	enterInlineFragment := h.EnterInlineFragment
+	h.EnterInlineFragment = func(c context.Context, v model.InlineFragment) error {
+		if enterInlineFragment != nil {
+			if err := enterInlineFragment(c, v); err != nil {
+				return err
+			}
+		}
+		return each(v.Directives(), model.DirectiveLocationInlineFragment)
+	}
+	return h
+}
+
+// fragmentSpreads returns all of the fragment spreads found in the
+// selection set, including those nested in fields and inline fragments.
+// Spreads in the fragments that are referenced are NOT followed.
+func fragmentSpreads(ch chan model.Selection) []model.FragmentSpread {
+	var list []model.FragmentSpread
+	for sel := range ch {
+		switch selectionKind(sel) {
+		case fieldSelection:
+			list = append(list, fragmentSpreads(sel.(model.SelectionField).Selections())...)
+		case inlineFragmentSelection:
+			list = append(list, fragmentSpreads(sel.(model.InlineFragment).Selections())...)
+		case fragmentSpreadSelection:
+			list = append(list, sel.(model.FragmentSpread))
+		}
+	}
+	return list
+}
+
+// selectionType identifies the kind of a model.Selection
+type selectionType int
+
+const (
+	unknownSelection selectionType = iota
+	fieldSelection
+	inlineFragmentSelection
+	fragmentSpreadSelection
+)
+
+// selectionKind returns the kind of `sel`. model.SelectionField also
+// satisfies model.FragmentSpread, so a type switch on selections must
+// check for fields first: use this instead
+func selectionKind(sel model.Selection) selectionType {
+	switch sel.(type) {
+	case model.SelectionField:
+		return fieldSelection
+	case model.InlineFragment:
+		return inlineFragmentSelection
+	case model.FragmentSpread:
+		return fragmentSpreadSelection
+	}
+	return unknownSelection
+}
+
+// recursivelyReferencedFragments records in `seen` the names of all
+// fragments that are reachable from the selection set, directly or
+// indirectly
+func (ctx *validationCtx) recursivelyReferencedFragments(ch chan model.Selection, seen map[string]struct{}) {
+	for _, spread := range fragmentSpreads(ch) {
+		if _, ok := seen[spread.Name()]; ok {
+			continue
+		}
+		seen[spread.Name()] = struct{}{}
+
+		if frag, ok := ctx.doc.LookupFragment(spread.Name()); ok {
+			ctx.recursivelyReferencedFragments(frag.Selections(), seen)
 		}
 	}
-	return nil
 }
@@ -1,6 +1,7 @@
 package validate_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -11,12 +12,78 @@ import (
 	"golang.org/x/net/context"
 )
 
-func TestValidate(t *testing.T) {
-	t.Run("Cannot spread fragment within itself", func(t *testing.T) {
+func validateSuccess(name, src string) (string, func(*testing.T)) {
+	return name, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		p := parser.New()
+		doc, err := p.ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return
+		}
+
+		if !assert.NoError(t, validate.Validate(ctx, schema.StarWars, doc), "document should validate") {
+			return
+		}
+	}
+}
+
+func validateFailure(name, src, expected string) (string, func(*testing.T)) {
+	return name, func(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		src := `{
+		p := parser.New()
+		doc, err := p.ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return
+		}
+
+		err = validate.Validate(ctx, schema.StarWars, doc)
+		if !assert.Error(t, err, "document should fail to validate") {
+			return
+		}
+
+		verrs, ok := err.(validate.Errors)
+		if !assert.True(t, ok, "error should be validate.Errors") {
+			return
+		}
+
+		var messages []string
+		for _, verr := range verrs {
+			messages = append(messages, verr.Message)
+		}
+		if !assert.Contains(t, messages, expected, "errors should contain the expected message") {
+			return
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Run(validateSuccess("Valid document", `query HeroAndFriends($episode: Episode, $withFriends: Boolean!) {
+  hero(episode: $episode) {
+    ...CharacterFields
+    friends @include(if: $withFriends) {
+      ...CharacterFields
+    }
+  }
+}
+
+query FetchHuman($id: String!) {
+  human(id: $id) {
+    ...CharacterFields
+    ... on Human @skip(if: false) {
+      homePlanet
+    }
+  }
+}
+
+fragment CharacterFields on Character {
+  id
+  name
+}`))
+	t.Run(validateFailure("Cannot spread fragment within itself", `{
   hero {
     ...NameAndAppearancesAndFriends
   }
@@ -28,15 +95,612 @@ fragment NameAndAppearancesAndFriends on Character {
   friends {
     ...NameAndAppearancesAndFriends
   }
-}`
-		p := parser.New()
+}`, `Cannot spread fragment "NameAndAppearancesAndFriends" within itself.`))
+	t.Run(validateFailure("Cannot spread fragment within itself indirectly", `{
+  hero {
+    ...A
+  }
+}
+
+fragment A on Character {
+  name
+  ...B
+}
+
+fragment B on Character {
+  friends {
+    ...C
+  }
+}
+
+fragment C on Character {
+  ...A
+}`, `Cannot spread fragment "A" within itself via "B", "C".`))
+	t.Run(validateFailure("Unique operation names", `query Hero {
+  hero {
+    name
+  }
+}
+
+query Hero {
+  hero {
+    id
+  }
+}`, `There can be only one operation named "Hero".`))
+	t.Run(validateFailure("Lone anonymous operation", `{
+  hero {
+    name
+  }
+}
+
+query Hero {
+  hero {
+    id
+  }
+}`, `This anonymous operation must be the only defined operation.`))
+	t.Run(validateFailure("Known fragment names", `{
+  hero {
+    ...UnknownFragment
+  }
+}`, `Unknown fragment "UnknownFragment".`))
+	t.Run(validateFailure("No unused fragments", `{
+  hero {
+    name
+  }
+}
+
+fragment Unused on Character {
+  id
+}`, `Fragment "Unused" is never used.`))
+	t.Run(validateFailure("Known argument names (field)", `{
+  hero(foo: "bar") {
+    name
+  }
+}`, `Unknown argument "foo" on field "Query.hero".`))
+	t.Run(validateFailure("Known argument names (interface field)", `{
+  hero {
+    name(foo: "bar")
+  }
+}`, `Unknown argument "foo" on field "Character.name".`))
+	t.Run(validateFailure("Known argument names (directive)", `{
+  hero {
+    name @skip(unless: true)
+  }
+}`, `Unknown argument "unless" on directive "@skip".`))
+	t.Run(validateFailure("Unique argument names", `{
+  human(id: "1000", id: "1001") {
+    name
+  }
+}`, `There can be only one argument named "id".`))
+	t.Run(validateFailure("Provided required arguments (field)", `{
+  human {
+    name
+  }
+}`, `Field "human" argument "id" of type "String!" is required, but it was not provided.`))
+	t.Run(validateFailure("Provided required arguments (directive)", `{
+  hero {
+    name @include
+  }
+}`, `Directive "@include" argument "if" of type "Boolean!" is required, but it was not provided.`))
+	t.Run(validateFailure("Unique variable names", `query Human($id: String!, $id: String!) {
+  human(id: $id) {
+    name
+  }
+}`, `There can be only one variable named "$id".`))
+	t.Run(validateFailure("No undefined variables", `query Human {
+  human(id: "1000") {
+    ...HumanFields
+  }
+}
+
+fragment HumanFields on Human {
+  name @include(if: $withName)
+}`, `Variable "$withName" is not defined by operation "Human".`))
+	t.Run(validateFailure("No undefined variables in lists", `query Human {
+  human(id: [{id: $id}]) {
+    name
+  }
+}`, `Variable "$id" is not defined by operation "Human".`))
+	t.Run(validateFailure("No unused variables", `query Human($id: String!, $unused: Int) {
+  human(id: $id) {
+    name
+  }
+}`, `Variable "$unused" is never used in operation "Human".`))
+	t.Run(validateFailure("Known directives", `{
+  hero @unknown {
+    name
+  }
+}`, `Unknown directive "@unknown".`))
+	t.Run(validateFailure("Directive in invalid location", `query Hero @skip(if: true) {
+  hero {
+    name
+  }
+}`, `Directive "@skip" may not be used on QUERY.`))
+	t.Run(validateSuccess("Introspection", `{
+  __schema {
+    queryType {
+      name
+    }
+  }
+  __type(name: "Droid") {
+    name
+    fields {
+      name
+    }
+  }
+  hero {
+    __typename
+  }
+}`))
+	t.Run(validateFailure("Fields on correct type", `{
+  hero {
+    nmae
+  }
+}`, `Cannot query field "nmae" on type "Character". Did you mean "name"?`))
+	t.Run(validateFailure("Fields on correct type (fragment)", `{
+  hero {
+    ... on Droid {
+      homePlanet
+    }
+  }
+}`, `Cannot query field "homePlanet" on type "Droid".`))
+	t.Run(validateFailure("Fields on correct type (introspection)", `{
+  __schema {
+    queryTyp {
+      name
+    }
+  }
+}`, `Cannot query field "queryTyp" on type "__Schema". Did you mean "queryType"?`))
+	t.Run(validateFailure("Leaf field with selection", `{
+  hero {
+    name {
+      length
+    }
+  }
+}`, `Field "name" must not have a selection since type "String" has no subfields.`))
+	t.Run(validateFailure("Enum field with selection", `{
+  hero {
+    appearsIn {
+      name
+    }
+  }
+}`, `Field "appearsIn" must not have a selection since type "[Episode]" has no subfields.`))
+	t.Run(validateFailure("Composite field without selection", `{
+  hero
+}`, `Field "hero" of type "Character" must have a selection of subfields. Did you mean "hero { ... }"?`))
+	t.Run(validateSuccess("Mergeable fields", `{
+  hero {
+    name
+    ...CharacterName
+    ... on Droid {
+      name
+      primaryFunction
+    }
+    ... on Human {
+      friends {
+        name
+      }
+    }
+    ... on Droid {
+      friends {
+        id
+      }
+    }
+  }
+  luke: human(id: "1000") {
+    name
+  }
+  leia: human(id: "1003") {
+    name
+  }
+}
+
+fragment CharacterName on Character {
+  name
+}`))
+	t.Run(validateFailure("Same response name, different fields", `{
+  hero {
+    name: id
+    name
+  }
+}`, `Fields "name" conflict because "id" and "name" are different fields. Use different aliases on the fields to fetch both if this was intentional.`))
+	t.Run(validateFailure("Same field, differing arguments", `{
+  human(id: "1000") {
+    name
+  }
+  human(id: "1003") {
+    name
+  }
+}`, `Fields "human" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional.`))
+	t.Run(validateFailure("Same field, differing list arguments", `{
+  human(id: ["1000", "1003"]) {
+    name
+  }
+  human(id: ["1000"]) {
+    name
+  }
+}`, `Fields "human" conflict because they have differing arguments. Use different aliases on the fields to fetch both if this was intentional.`))
+	t.Run(validateFailure("Conflict through fragments", `{
+  hero {
+    ...A
+    ...B
+  }
+}
+
+fragment A on Character {
+  x: name
+}
+
+fragment B on Character {
+  x: id
+}`, `Fields "x" conflict because "name" and "id" are different fields. Use different aliases on the fields to fetch both if this was intentional.`))
+	t.Run(validateFailure("Conflicting subfields", `{
+  hero {
+    friends {
+      x: name
+    }
+    friends {
+      x: id
+    }
+  }
+}`, `Fields "friends" conflict because subfields "x" conflict because "name" and "id" are different fields. Use different aliases on the fields to fetch both if this was intentional.`))
+	t.Run(validateFailure("Conflicting types", `{
+  hero {
+    ... on Human {
+      x: homePlanet
+    }
+    ... on Droid {
+      x: appearsIn
+    }
+  }
+}`, `Fields "x" conflict because they return conflicting types "String" and "[Episode]". Use different aliases on the fields to fetch both if this was intentional.`))
+}
+
+func TestValidateErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	doc, err := p.ParseString(ctx, `query Hero($unused: Int) {
+  hero {
+    nmae
+    friends {
+      nam
+    }
+  }
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		return
+	}
+
+	err = validate.Validate(ctx, schema.StarWars, doc)
+	verrs, ok := err.(validate.Errors)
+	if !assert.True(t, ok, "error should be validate.Errors") {
+		return
+	}
+
+	if !assert.Len(t, verrs, 3, "all errors should be reported") {
+		return
+	}
+
+	buf, err := json.Marshal(verrs)
+	if !assert.NoError(t, err, "json.Marshal should succeed") {
+		return
+	}
+
+	const expected = `[
+  {"message":"Variable \"$unused\" is never used in operation \"Hero\".","locations":[{"line":1,"column":12}],"extensions":{"rule":"NoUnusedVariables"}},
+  {"message":"Cannot query field \"nmae\" on type \"Character\". Did you mean \"name\"?","locations":[{"line":3,"column":5}],"path":["hero","nmae"],"extensions":{"rule":"FieldsOnCorrectType"}},
+  {"message":"Cannot query field \"nam\" on type \"Character\". Did you mean \"name\"?","locations":[{"line":5,"column":7}],"path":["hero","friends","nam"],"extensions":{"rule":"FieldsOnCorrectType"}}
+]`
+	if !assert.JSONEq(t, expected, string(buf), "JSON should match") {
+		return
+	}
+}
+
+func TestValidateSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `schema {
+  query: Query
+  subscription: Subscription
+}
+
+type Review {
+  stars: Int
+}
+
+type Query {
+  latestReview: Review
+}
+
+type Subscription {
+  reviewAdded(episode: String): Review
+  ping: String
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	check := func(src string) error {
+		doc, err := p.ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return nil
+		}
+		return validate.Validate(ctx, s, doc)
+	}
+
+	t.Run("Single field", func(t *testing.T) {
+		err := check(`subscription OnReview($ep: String) {
+  reviewAdded(episode: $ep) {
+    stars
+  }
+}`)
+		if !assert.NoError(t, err, "document should validate") {
+			return
+		}
+	})
+	t.Run("Single field through a fragment", func(t *testing.T) {
+		err := check(`subscription {
+  ...Ping
+}
+
+fragment Ping on Subscription {
+  ping
+  ping
+}`)
+		if !assert.NoError(t, err, "document should validate") {
+			return
+		}
+	})
+
+	for _, tc := range []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "Multiple fields",
+			src:      `subscription OnReview { reviewAdded { stars } ping }`,
+			expected: `Subscription "OnReview" must select only one top level field.`,
+		},
+		{
+			name: "Multiple fields through a fragment",
+			src: `subscription { ...Fields }
+
+fragment Fields on Subscription {
+  ... on Subscription {
+    pong: ping
+  }
+  ping
+}`,
+			expected: `Anonymous Subscription must select only one top level field.`,
+		},
+		{
+			name:     "Introspection field",
+			src:      `subscription OnTypename { __typename }`,
+			expected: `Subscription "OnTypename" must not select an introspection top level field.`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := check(tc.src)
+			verrs, ok := err.(validate.Errors)
+			if !assert.True(t, ok, "error should be validate.Errors") {
+				return
+			}
+			if !assert.Len(t, verrs, 1, "there should be exactly one error") {
+				t.Logf("%s", verrs)
+				return
+			}
+			if !assert.Equal(t, tc.expected, verrs[0].Message, "message should match") {
+				return
+			}
+			if !assert.Equal(t, "SingleFieldSubscriptions", verrs[0].Rule, "rule should match") {
+				return
+			}
+		})
+	}
+}
+
+func TestValidateDirectiveDefinitions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	s, err := p.ParseString(ctx, `directive @cached(ttl: Int!, scope: String = "public") on FIELD | QUERY
+
+directive @tag(name: String!) repeatable on FIELD
+
+type Query {
+  ping: String
+}`)
+	if !assert.NoError(t, err, "p.Parse should succeed (schema)") {
+		return
+	}
+
+	check := func(src string) error {
 		doc, err := p.ParseString(ctx, src)
-		if !assert.NoError(t, err, "p.Parse should succed") {
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return nil
+		}
+		return validate.Validate(ctx, s, doc)
+	}
+
+	t.Run("Valid usage", func(t *testing.T) {
+		err := check(`query Ping @cached(ttl: 60) {
+  ping @tag(name: "a") @tag(name: "b") @skip(if: false)
+}`)
+		if !assert.NoError(t, err, "document should validate") {
 			return
 		}
+	})
+
+	for _, tc := range []struct {
+		name     string
+		src      string
+		rule     string
+		expected string
+	}{
+		{
+			name:     "Invalid location",
+			src:      `{ ... @cached(ttl: 60) { ping } }`,
+			rule:     "KnownDirectives",
+			expected: `Directive "@cached" may not be used on INLINE_FRAGMENT.`,
+		},
+		{
+			name:     "Invalid location on variable definition",
+			src:      `query Q($skip: Boolean! @cached(ttl: 60)) { ping @skip(if: $skip) }`,
+			rule:     "KnownDirectives",
+			expected: `Directive "@cached" may not be used on VARIABLE_DEFINITION.`,
+		},
+		{
+			name:     "Unknown argument",
+			src:      `{ ping @cached(ttl: 60, ttl2: 60) }`,
+			rule:     "KnownArgumentNames",
+			expected: `Unknown argument "ttl2" on directive "@cached".`,
+		},
+		{
+			name:     "Missing required argument",
+			src:      `{ ping @cached }`,
+			rule:     "ProvidedRequiredArguments",
+			expected: `Directive "@cached" argument "ttl" of type "Int!" is required, but it was not provided.`,
+		},
+		{
+			name:     "Non-repeatable directive used twice",
+			src:      `{ ping @cached(ttl: 1) @cached(ttl: 2) }`,
+			rule:     "UniqueDirectivesPerLocation",
+			expected: `The directive "@cached" can only be used once at this location.`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := check(tc.src)
+			verrs, ok := err.(validate.Errors)
+			if !assert.True(t, ok, "error should be validate.Errors") {
+				return
+			}
+			if !assert.Len(t, verrs, 1, "there should be exactly one error") {
+				t.Logf("%s", verrs)
+				return
+			}
+			if !assert.Equal(t, tc.expected, verrs[0].Message, "message should match") {
+				return
+			}
+			if !assert.Equal(t, tc.rule, verrs[0].Rule, "rule should match") {
+				return
+			}
+		})
+	}
+}
+
+func TestValidateSchemaDefaultValues(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p := parser.New()
+	check := func(src string) error {
+		s, err := p.ParseString(ctx, src)
+		if !assert.NoError(t, err, "p.Parse should succeed") {
+			return nil
+		}
+		return validate.ValidateSchema(ctx, s)
+	}
+
+	t.Run("Valid defaults", func(t *testing.T) {
+		err := check(`enum Order { ASC DESC }
 
-		if !assert.Error(t, validate.Validate(ctx, schema.StarWars, doc), "document should fail to validate") {
+input Filter {
+  query: String!
+  limit: Int = 10
+  order: Order = ASC
+  tags: [String] = "all"
+}
+
+directive @cached(ttl: Int = 60) on FIELD
+
+interface Node {
+  children(first: Int = 10, order: Order = DESC): [Node]
+}
+
+type Query {
+  search(filter: Filter = {query: "all"}, ratio: Float = 1, ids: [ID!] = [1, "2"]): String
+}`)
+		if !assert.NoError(t, err, "schema should validate") {
 			return
 		}
 	})
+
+	for _, tc := range []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "Argument of wrong scalar type",
+			src:      `type Query { hero(first: Int = "ten"): String }`,
+			expected: `Invalid default value for argument "Query.hero(first:)": Expected value of type "Int", found "ten".`,
+		},
+		{
+			name:     "Interface field argument",
+			src:      `interface Node { children(first: Int = "ten"): [Node] }`,
+			expected: `Invalid default value for argument "Node.children(first:)": Expected value of type "Int", found "ten".`,
+		},
+		{
+			name:     "Null for non-null input field",
+			src:      `input Filter { limit: Int! = null }`,
+			expected: `Invalid default value for input field "Filter.limit": Expected value of type "Int!", found null.`,
+		},
+		{
+			name:     "Unknown enum value",
+			src:      "enum Order { ASC DESC }\n\ninput Filter { order: Order = UP }",
+			expected: `Invalid default value for input field "Filter.order": Expected value of type "Order", found UP.`,
+		},
+		{
+			name:     "Wrong list element",
+			src:      `type Query { sum(values: [Int] = [1, true]): Int }`,
+			expected: `Invalid default value for argument "Query.sum(values:)": Expected value of type "Int", found true.`,
+		},
+		{
+			name:     "Missing required input field",
+			src:      "input Filter { query: String! }\n\ntype Query { search(filter: Filter = {}): String }",
+			expected: `Invalid default value for argument "Query.search(filter:)": Field "Filter.query" of required type "String!" was not provided.`,
+		},
+		{
+			name:     "Unknown input field",
+			src:      "input Filter { query: String }\n\ntype Query { search(filter: Filter = {limit: 1}): String }",
+			expected: `Invalid default value for argument "Query.search(filter:)": Field "limit" is not defined by type "Filter".`,
+		},
+		{
+			name:     "Directive argument",
+			src:      `directive @cached(ttl: Int = 1.5) on FIELD`,
+			expected: `Invalid default value for argument "@cached(ttl:)": Expected value of type "Int", found 1.5.`,
+		},
+		{
+			name:     "Extension field",
+			src:      "input Filter { query: String }\n\nextend input Filter { limit: Int = false }",
+			expected: `Invalid default value for input field "Filter.limit": Expected value of type "Int", found false.`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := check(tc.src)
+			verrs, ok := err.(validate.Errors)
+			if !assert.True(t, ok, "error should be validate.Errors") {
+				return
+			}
+			if !assert.Len(t, verrs, 1, "there should be exactly one error") {
+				t.Logf("%s", verrs)
+				return
+			}
+			if !assert.Equal(t, tc.expected, verrs[0].Message, "message should match") {
+				return
+			}
+			if !assert.Equal(t, "DefaultValuesOfCorrectType", verrs[0].Rule, "rule should match") {
+				return
+			}
+		})
+	}
 }
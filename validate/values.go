@@ -0,0 +1,154 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/lestrrat/go-graphql/format"
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// defaultValuesOfCorrectType checks that the default values of the
+// arguments of fields and directives, and of the fields of input
+// types, can be coerced to the types that they are declared with
+func defaultValuesOfCorrectType(ctx *validationCtx) *visitor.Handler {
+	// owner is the coordinate of the field or the directive whose
+	// arguments are being visited, e.g. "Query.hero" or "@cached"
+	var typeName, owner string
+
+	check := func(kind, coordinate string, v interface {
+		model.Typer
+		model.DefaultValuer
+	}) {
+		if !v.HasDefaultValue() {
+			return
+		}
+		if problem := ctx.checkValue(v.Type(), v.DefaultValue()); problem != "" {
+			ctx.reportf(v, `Invalid default value for %s "%s": %s`, kind, coordinate, problem)
+		}
+	}
+
+	return &visitor.Handler{
+		EnterObjectDefinition: func(_ context.Context, v model.ObjectDefinition) error {
+			typeName = v.Name()
+			return nil
+		},
+		EnterObjectExtension: func(_ context.Context, v model.ObjectExtension) error {
+			typeName = v.Name()
+			return nil
+		},
+		EnterInterfaceDefinition: func(_ context.Context, v model.InterfaceDefinition) error {
+			typeName = v.Name()
+			return nil
+		},
+		EnterInterfaceExtension: func(_ context.Context, v model.InterfaceExtension) error {
+			typeName = v.Name()
+			return nil
+		},
+		EnterInputDefinition: func(_ context.Context, v model.InputDefinition) error {
+			typeName = v.Name()
+			return nil
+		},
+		EnterInputExtension: func(_ context.Context, v model.InputExtension) error {
+			typeName = v.Name()
+			return nil
+		},
+		EnterObjectFieldDefinition: func(_ context.Context, v model.ObjectFieldDefinition) error {
+			owner = typeName + "." + v.Name()
+			return nil
+		},
+		EnterInterfaceFieldDefinition: func(_ context.Context, v model.InterfaceFieldDefinition) error {
+			owner = typeName + "." + v.Name()
+			return nil
+		},
+		EnterDirectiveDefinition: func(_ context.Context, v model.DirectiveDefinition) error {
+			owner = "@" + v.Name()
+			return nil
+		},
+		EnterObjectFieldArgumentDefinition: func(_ context.Context, v model.ObjectFieldArgumentDefinition) error {
+			check("argument", fmt.Sprintf("%s(%s:)", owner, v.Name()), v)
+			return nil
+		},
+		EnterInputFieldDefinition: func(_ context.Context, v model.InputFieldDefinition) error {
+			check("input field", typeName+"."+v.Name(), v)
+			return nil
+		},
+	}
+}
+
+// checkValue returns a description of the reason why the constant
+// value `v` can not be used as a value of type `typ`, or an empty
+// string if it can. Values of types that are not known are accepted
+func (ctx *validationCtx) checkValue(typ model.Type, v model.Value) string {
+	if v.Kind() == model.VariableKind {
+		return ""
+	}
+
+	if v.Kind() == model.NullKind {
+		if !model.IsNullable(typ) {
+			return fmt.Sprintf(`Expected value of type "%s", found null.`, model.TypeString(typ))
+		}
+		return ""
+	}
+
+	if lt, ok := typ.(model.ListType); ok {
+		if v.Kind() != model.ListKind {
+			// A single value is accepted where a list is expected
+			return ctx.checkValue(lt.Type(), v)
+		}
+		for elem := range v.(model.ListValue).Values() {
+			if problem := ctx.checkValue(lt.Type(), elem); problem != "" {
+				return problem
+			}
+		}
+		return ""
+	}
+
+	def := ctx.lookupType(typ)
+	switch def.(type) {
+	case model.ScalarDefinition:
+		if _, err := def.(model.ScalarDefinition).ParseLiteral(v); err != nil {
+			return fmt.Sprintf(`Expected value of type "%s", found %s.`, model.TypeString(typ), format.Value(v))
+		}
+	case model.EnumDefinition:
+		if v.Kind() == model.EnumKind {
+			for e := range def.(model.EnumDefinition).Elements() {
+				if e.Name() == v.Value().(string) {
+					return ""
+				}
+			}
+		}
+		return fmt.Sprintf(`Expected value of type "%s", found %s.`, model.TypeString(typ), format.Value(v))
+	case model.InputDefinition:
+		idef := def.(model.InputDefinition)
+		if v.Kind() != model.ObjectKind {
+			return fmt.Sprintf(`Expected value of type "%s", found %s.`, model.TypeString(typ), format.Value(v))
+		}
+
+		fields := make(map[string]model.InputFieldDefinition)
+		for f := range idef.Fields() {
+			fields[f.Name()] = f
+		}
+
+		given := make(map[string]struct{})
+		for of := range v.(model.ObjectValue).Fields() {
+			f, ok := fields[of.Name()]
+			if !ok {
+				return fmt.Sprintf(`Field "%s" is not defined by type "%s".`, of.Name(), idef.Name())
+			}
+			given[of.Name()] = struct{}{}
+			if problem := ctx.checkValue(f.Type(), of.Value()); problem != "" {
+				return problem
+			}
+		}
+
+		for f := range idef.Fields() {
+			if _, ok := given[f.Name()]; ok || model.IsNullable(f.Type()) || f.HasDefaultValue() {
+				continue
+			}
+			return fmt.Sprintf(`Field "%s.%s" of required type "%s" was not provided.`, idef.Name(), f.Name(), model.TypeString(f.Type()))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"fmt"
+
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/visitor"
+	"golang.org/x/net/context"
+)
+
+// uniqueVariableNames checks that each operation defines its
+// variables only once
+func uniqueVariableNames(ctx *validationCtx) *visitor.Handler {
+	return &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			seen := make(map[string]model.VariableDefinition)
+			for vdef := range v.Variables() {
+				if prev, ok := seen[vdef.Name()]; ok {
+					ctx.report(fmt.Sprintf(`There can be only one variable named "$%s".`, vdef.Name()), prev, vdef)
+					continue
+				}
+				seen[vdef.Name()] = vdef
+			}
+			return nil
+		},
+	}
+}
+
+// noUndefinedVariables checks that all variables used in an
+// operation, including those used in the fragments that it spreads,
+// are defined by that operation
+func noUndefinedVariables(ctx *validationCtx) *visitor.Handler {
+	return &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			defined := make(map[string]struct{})
+			for vdef := range v.Variables() {
+				defined[vdef.Name()] = struct{}{}
+			}
+
+			for _, name := range ctx.variableUsages(v) {
+				if _, ok := defined[name]; ok {
+					continue
+				}
+				if v.HasName() {
+					ctx.reportf(v, `Variable "$%s" is not defined by operation "%s".`, name, v.Name())
+					continue
+				}
+				ctx.reportf(v, `Variable "$%s" is not defined.`, name)
+			}
+			return nil
+		},
+	}
+}
+
+// noUnusedVariables checks that all variables defined by an operation
+// are used, either in the operation or in the fragments that it spreads
+func noUnusedVariables(ctx *validationCtx) *visitor.Handler {
+	return &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			used := make(map[string]struct{})
+			for _, name := range ctx.variableUsages(v) {
+				used[name] = struct{}{}
+			}
+
+			for vdef := range v.Variables() {
+				if _, ok := used[vdef.Name()]; ok {
+					continue
+				}
+				if v.HasName() {
+					ctx.reportf(vdef, `Variable "$%s" is never used in operation "%s".`, vdef.Name(), v.Name())
+					continue
+				}
+				ctx.reportf(vdef, `Variable "$%s" is never used.`, vdef.Name())
+			}
+			return nil
+		},
+	}
+}
+
+// variableUsages returns the names of the variables used in the
+// operation, including those used in the fragments that it spreads
+func (ctx *validationCtx) variableUsages(op model.OperationDefinition) []string {
+	var names []string
+	names = appendDirectiveVariables(names, op.Directives())
+	names = ctx.appendSelectionVariables(names, op.Selections(), make(map[string]struct{}))
+	return names
+}
+
+func (ctx *validationCtx) appendSelectionVariables(names []string, ch chan model.Selection, seen map[string]struct{}) []string {
+	for sel := range ch {
+		switch selectionKind(sel) {
+		case fieldSelection:
+			f := sel.(model.SelectionField)
+			for arg := range f.Arguments() {
+				names = appendValueVariables(names, arg.Value())
+			}
+			names = appendDirectiveVariables(names, f.Directives())
+			names = ctx.appendSelectionVariables(names, f.Selections(), seen)
+		case inlineFragmentSelection:
+			frag := sel.(model.InlineFragment)
+			names = appendDirectiveVariables(names, frag.Directives())
+			names = ctx.appendSelectionVariables(names, frag.Selections(), seen)
+		case fragmentSpreadSelection:
+			spread := sel.(model.FragmentSpread)
+			names = appendDirectiveVariables(names, spread.Directives())
+			if _, ok := seen[spread.Name()]; ok {
+				continue
+			}
+			seen[spread.Name()] = struct{}{}
+
+			if frag, ok := ctx.doc.LookupFragment(spread.Name()); ok {
+				names = appendDirectiveVariables(names, frag.Directives())
+				names = ctx.appendSelectionVariables(names, frag.Selections(), seen)
+			}
+		}
+	}
+	return names
+}
+
+func appendDirectiveVariables(names []string, ch chan model.Directive) []string {
+	for d := range ch {
+		for arg := range d.Arguments() {
+			names = appendValueVariables(names, arg.Value())
+		}
+	}
+	return names
+}
+
+func appendValueVariables(names []string, v model.Value) []string {
+	switch v.Kind() {
+	case model.VariableKind:
+		names = append(names, v.Value().(string))
+	case model.ListKind:
+		for elem := range v.(model.ListValue).Values() {
+			names = appendValueVariables(names, elem)
+		}
+	case model.ObjectKind:
+		for f := range v.(model.ObjectValue).Fields() {
+			names = appendValueVariables(names, f.Value())
+		}
+	}
+	return names
+}
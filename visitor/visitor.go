@@ -41,32 +41,64 @@ type Handler struct {
 	LeaveDefinition func(context.Context, model.Definition) error
 
 	// EnterDirectiveList is called when starting to traverse a
-	// list of `model.Directive`s.
+	// list of `model.Directive`s, on executable nodes as well as on
+	// type-system definitions.
 	EnterDirectiveList func(context.Context) error
 
 	// LeaveDirectiveList is called when leaving a list of `model.Directive`s.
 	LeaveDirectiveList func(context.Context) error
 
 	// EnterDirective is called when starting to visit an Directive node.
-	// Arguments are not visited.
+	// Arguments are visited afterward.
 	// Does NOT respect the Pruner return value
 	EnterDirective func(context.Context, model.Directive) error
 
 	// LeaveDirective is called when leaving a model.Directive node.
 	LeaveDirective func(context.Context, model.Directive) error
 
+	// EnterArgument is called when starting to visit a model.Argument node
+	// of a field or a directive. The value of the argument is visited afterward
+	EnterArgument func(context.Context, model.Argument) error
+
+	// LeaveArgument is called when leaving a model.Argument node.
+	LeaveArgument func(context.Context, model.Argument) error
+
+	// EnterValue is called when starting to visit a model.Value node.
+	// The elements of a model.ListValue, and the values of the fields
+	// of a model.ObjectValue are visited afterward
+	EnterValue func(context.Context, model.Value) error
+
+	// LeaveValue is called when leaving a model.Value node.
+	LeaveValue func(context.Context, model.Value) error
+
 	// EnterOperationDefinition is called when starting to visit a model.OperationDefinition node
-	// node. Selections within the definitions are followed. Variable definitions
-	// are NOT followed.
+	// node. Variable definitions are visited afterward, followed by the
+	// directives and the selections within the definition.
 	EnterOperationDefinition func(context.Context, model.OperationDefinition) error
 
 	// LeaveOperationDefinition is called when leaving a model.OperationDefinition node.
 	LeaveOperationDefinition func(context.Context, model.OperationDefinition) error
 
-	// EnterVariableDefinition is called when starting to visit an VariableDefinition
-	EnterFragmentDefinition func(context.Context, model.FragmentDefinition) error
+	// EnterVariableDefinitionList is called when starting to traverse the
+	// list of `model.VariableDefinition`s of an operation. It is not called
+	// for operations that do not define any variables.
+	EnterVariableDefinitionList func(context.Context) error
+
+	// LeaveVariableDefinitionList is called when leaving a list of `model.VariableDefinition`s.
+	LeaveVariableDefinitionList func(context.Context) error
+
+	// EnterVariableDefinition is called when starting to visit a model.VariableDefinition node.
+	// The default value and the directives of the variable are visited afterward
+	EnterVariableDefinition func(context.Context, model.VariableDefinition) error
 
 	// LeaveVariableDefinition is called when leaving a model.VariableDefinition node.
+	LeaveVariableDefinition func(context.Context, model.VariableDefinition) error
+
+	// EnterFragmentDefinition is called when starting to visit a model.FragmentDefinition node.
+	// The directives and the selections of the fragment are visited afterward
+	EnterFragmentDefinition func(context.Context, model.FragmentDefinition) error
+
+	// LeaveFragmentDefinition is called when leaving a model.FragmentDefinition node.
 	LeaveFragmentDefinition func(context.Context, model.FragmentDefinition) error
 
 	// EnterObjectDefinition is called when starting to visit a model.ObjectDefinition node.
@@ -75,6 +107,15 @@ type Handler struct {
 	// LeaveObjectDefinition is called when leaving a model.ObjectDefinition node.
 	LeaveObjectDefinition func(context.Context, model.ObjectDefinition) error
 
+	// EnterImplementedInterface is called when starting to visit one of
+	// the interfaces implemented by an object type or an interface
+	// (including their extensions), before their fields are visited
+	EnterImplementedInterface func(context.Context, model.NamedType) error
+
+	// LeaveImplementedInterface is called when leaving an interface
+	// implemented by an object type or an interface.
+	LeaveImplementedInterface func(context.Context, model.NamedType) error
+
 	// EnterObjectFieldDefinitionList is called when starting to traverse a
 	// list of `model.ObjectFieldDefinition`s.
 	EnterObjectFieldDefinitionList func(context.Context) error
@@ -88,6 +129,13 @@ type Handler struct {
 	// LeaveObjectFieldDefinition is called when leaving a model.ObjectFieldDefinition node.
 	LeaveObjectFieldDefinition func(context.Context, model.ObjectFieldDefinition) error
 
+	// EnterObjectFieldArgumentDefinition is called when starting to visit a model.ObjectFieldArgumentDefinition node.
+	// The default value and the directives of the argument are visited afterward
+	EnterObjectFieldArgumentDefinition func(context.Context, model.ObjectFieldArgumentDefinition) error
+
+	// LeaveObjectFieldArgumentDefinition is called when leaving a model.ObjectFieldArgumentDefinition node.
+	LeaveObjectFieldArgumentDefinition func(context.Context, model.ObjectFieldArgumentDefinition) error
+
 	// EnterInterfaceDefinition is called when starting to visit a model.InterfaceDefinition node.
 	EnterInterfaceDefinition func(context.Context, model.InterfaceDefinition) error
 
@@ -106,6 +154,12 @@ type Handler struct {
 	// LeaveEnumDefinition is called when leaving a model.EnumDefinition node.
 	LeaveEnumDefinition func(context.Context, model.EnumDefinition) error
 
+	// EnterEnumElementDefinition is called when starting to visit a model.EnumElementDefinition node.
+	EnterEnumElementDefinition func(context.Context, model.EnumElementDefinition) error
+
+	// LeaveEnumElementDefinition is called when leaving a model.EnumElementDefinition node.
+	LeaveEnumElementDefinition func(context.Context, model.EnumElementDefinition) error
+
 	// EnterUnionDefinition is called when starting to visit an UnionDefinition node.
 	EnterUnionDefinition func(context.Context, model.UnionDefinition) error
 
@@ -126,11 +180,67 @@ type Handler struct {
 	LeaveInputFieldDefinitionList func(context.Context) error
 
 	// EnterInputFieldDefinition is called when starting to visit a model.InputFieldDefinition node.
+	// The default value and the directives of the field are visited afterward
 	EnterInputFieldDefinition func(context.Context, model.InputFieldDefinition) error
 
 	// LeaveInputFieldDefinition is called when leaving a model.InputFieldDefinition node.
 	LeaveInputFieldDefinition func(context.Context, model.InputFieldDefinition) error
 
+	// EnterScalarDefinition is called when starting to visit a model.ScalarDefinition node.
+	EnterScalarDefinition func(context.Context, model.ScalarDefinition) error
+
+	// LeaveScalarDefinition is called when leaving a model.ScalarDefinition node.
+	LeaveScalarDefinition func(context.Context, model.ScalarDefinition) error
+
+	// EnterDirectiveDefinition is called when starting to visit a model.DirectiveDefinition node.
+	// The argument definitions of the directive are visited afterward
+	EnterDirectiveDefinition func(context.Context, model.DirectiveDefinition) error
+
+	// LeaveDirectiveDefinition is called when leaving a model.DirectiveDefinition node.
+	LeaveDirectiveDefinition func(context.Context, model.DirectiveDefinition) error
+
+	// EnterObjectExtension is called when starting to visit a model.ObjectExtension node.
+	EnterObjectExtension func(context.Context, model.ObjectExtension) error
+
+	// LeaveObjectExtension is called when leaving a model.ObjectExtension node.
+	LeaveObjectExtension func(context.Context, model.ObjectExtension) error
+
+	// EnterInterfaceExtension is called when starting to visit a model.InterfaceExtension node.
+	EnterInterfaceExtension func(context.Context, model.InterfaceExtension) error
+
+	// LeaveInterfaceExtension is called when leaving a model.InterfaceExtension node.
+	LeaveInterfaceExtension func(context.Context, model.InterfaceExtension) error
+
+	// EnterUnionExtension is called when starting to visit a model.UnionExtension node.
+	EnterUnionExtension func(context.Context, model.UnionExtension) error
+
+	// LeaveUnionExtension is called when leaving a model.UnionExtension node.
+	LeaveUnionExtension func(context.Context, model.UnionExtension) error
+
+	// EnterEnumExtension is called when starting to visit a model.EnumExtension node.
+	EnterEnumExtension func(context.Context, model.EnumExtension) error
+
+	// LeaveEnumExtension is called when leaving a model.EnumExtension node.
+	LeaveEnumExtension func(context.Context, model.EnumExtension) error
+
+	// EnterInputExtension is called when starting to visit a model.InputExtension node.
+	EnterInputExtension func(context.Context, model.InputExtension) error
+
+	// LeaveInputExtension is called when leaving a model.InputExtension node.
+	LeaveInputExtension func(context.Context, model.InputExtension) error
+
+	// EnterScalarExtension is called when starting to visit a model.ScalarExtension node.
+	EnterScalarExtension func(context.Context, model.ScalarExtension) error
+
+	// LeaveScalarExtension is called when leaving a model.ScalarExtension node.
+	LeaveScalarExtension func(context.Context, model.ScalarExtension) error
+
+	// EnterSchemaExtension is called when starting to visit a model.SchemaExtension node.
+	EnterSchemaExtension func(context.Context, model.SchemaExtension) error
+
+	// LeaveSchemaExtension is called when leaving a model.SchemaExtension node.
+	LeaveSchemaExtension func(context.Context, model.SchemaExtension) error
+
 	// EnterSelectionList is called when starting to traverse a
 	// list of `model.Selection`s.
 	EnterSelectionList func(context.Context) error
@@ -203,6 +313,10 @@ func visitSchema(ctx context.Context, h *Handler, v model.Schema) error {
 		}
 	}
 
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
 	if hfunc := h.LeaveSchema; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
 			return errors.Wrap(err, `failed to visit document (leave)`)
@@ -301,10 +415,22 @@ func visitDefinition(ctx context.Context, h *Handler, v model.Definition) error
 			if err := visitInputDefinition(ctx, h, v.(model.InputDefinition)); err != nil {
 				return errors.Wrap(err, `failed to visit input definition`)
 			}
+		case model.ScalarDefinition:
+			if err := visitScalarDefinition(ctx, h, v.(model.ScalarDefinition)); err != nil {
+				return errors.Wrap(err, `failed to visit scalar definition`)
+			}
+		case model.DirectiveDefinition:
+			if err := visitDirectiveDefinition(ctx, h, v.(model.DirectiveDefinition)); err != nil {
+				return errors.Wrap(err, `failed to visit directive definition`)
+			}
 		case model.Schema:
 			if err := visitSchema(ctx, h, v.(model.Schema)); err != nil {
 				return errors.Wrap(err, `failed to visit schema`)
 			}
+		case model.Extension:
+			if err := visitExtension(ctx, h, v.(model.Extension)); err != nil {
+				return errors.Wrap(err, `failed to visit extension`)
+			}
 		default:
 			return errors.Errorf(`unknown definition %T`, v)
 		}
@@ -330,6 +456,14 @@ func visitOperationDefinition(ctx context.Context, h *Handler, v model.Operation
 	}
 
 	if !prune {
+		if err := visitVariableDefinitionList(ctx, h, v.Variables()); err != nil {
+			return errors.Wrap(err, `failed to visit variable definition list`)
+		}
+
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
 		if err := visitSelectionList(ctx, h, v.Selections()); err != nil {
 			return errors.Wrap(err, `failed to visit selection list`)
 		}
@@ -343,6 +477,60 @@ func visitOperationDefinition(ctx context.Context, h *Handler, v model.Operation
 	return nil
 }
 
+func visitVariableDefinitionList(ctx context.Context, h *Handler, ch chan model.VariableDefinition) error {
+	if len(ch) == 0 {
+		return nil
+	}
+	if hfunc := h.EnterVariableDefinitionList; hfunc != nil {
+		if err := hfunc(ctx); err != nil {
+			return errors.Wrap(err, `failed to visit variable definition list (enter)`)
+		}
+	}
+	for vdef := range ch {
+		if err := visitVariableDefinition(ctx, h, vdef); err != nil {
+			return errors.Wrap(err, `failed to visit variable definition`)
+		}
+	}
+	if hfunc := h.LeaveVariableDefinitionList; hfunc != nil {
+		if err := hfunc(ctx); err != nil {
+			return errors.Wrap(err, `failed to visit variable definition list (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitVariableDefinition(ctx context.Context, h *Handler, v model.VariableDefinition) error {
+	var prune bool
+	if hfunc := h.EnterVariableDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit variable definition (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if v.HasDefaultValue() {
+			if err := visitValue(ctx, h, v.DefaultValue()); err != nil {
+				return errors.Wrap(err, `failed to visit default value`)
+			}
+		}
+
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+	}
+
+	if hfunc := h.LeaveVariableDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit variable definition (leave)`)
+		}
+	}
+	return nil
+}
+
 func visitSelectionList(ctx context.Context, h *Handler, ch chan model.Selection) error {
 	if len(ch) == 0 {
 		return nil
@@ -417,6 +605,10 @@ func visitSelectionField(ctx context.Context, h *Handler, v model.SelectionField
 	}
 
 	if !prune {
+		if err := visitArgumentList(ctx, h, v.Arguments()); err != nil {
+			return errors.Wrap(err, `failed to visit argument list`)
+		}
+
 		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
 			return errors.Wrap(err, `failed to visit directive list`)
 		}
@@ -466,6 +658,10 @@ func visitDirective(ctx context.Context, h *Handler, v model.Directive) error {
 		}
 	}
 
+	if err := visitArgumentList(ctx, h, v.Arguments()); err != nil {
+		return errors.Wrap(err, `failed to visit argument list`)
+	}
+
 	if hfunc := h.LeaveDirective; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
 			return errors.Wrap(err, `failed to visit directive (leave)`)
@@ -474,6 +670,78 @@ func visitDirective(ctx context.Context, h *Handler, v model.Directive) error {
 	return nil
 }
 
+func visitArgumentList(ctx context.Context, h *Handler, ch chan model.Argument) error {
+	for arg := range ch {
+		if err := visitArgument(ctx, h, arg); err != nil {
+			return errors.Wrap(err, `failed to visit argument`)
+		}
+	}
+	return nil
+}
+
+func visitArgument(ctx context.Context, h *Handler, v model.Argument) error {
+	var prune bool
+	if hfunc := h.EnterArgument; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit argument (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitValue(ctx, h, v.Value()); err != nil {
+			return errors.Wrap(err, `failed to visit value`)
+		}
+	}
+
+	if hfunc := h.LeaveArgument; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit argument (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitValue(ctx context.Context, h *Handler, v model.Value) error {
+	var prune bool
+	if hfunc := h.EnterValue; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit value (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		switch v.Kind() {
+		case model.ListKind:
+			for elem := range v.(model.ListValue).Values() {
+				if err := visitValue(ctx, h, elem); err != nil {
+					return errors.Wrap(err, `failed to visit list element`)
+				}
+			}
+		case model.ObjectKind:
+			for field := range v.(model.ObjectValue).Fields() {
+				if err := visitValue(ctx, h, field.Value()); err != nil {
+					return errors.Wrapf(err, `failed to visit object field %s`, field.Name())
+				}
+			}
+		}
+	}
+
+	if hfunc := h.LeaveValue; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit value (leave)`)
+		}
+	}
+	return nil
+}
+
 func visitFragmentSpread(ctx context.Context, h *Handler, v model.FragmentSpread) error {
 	if hfunc := h.EnterFragmentSpread; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
@@ -586,6 +854,14 @@ func visitObjectDefinition(ctx context.Context, h *Handler, v model.ObjectDefini
 	}
 
 	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
+		if err := visitImplementedInterfaces(ctx, h, v.Interfaces()); err != nil {
+			return errors.Wrap(err, `failed to visit implemented interfaces`)
+		}
+
 		if err := visitObjectFieldDefinitionList(ctx, h, v.Fields()); err != nil {
 			return errors.Wrap(err, `failed to visit object definition list`)
 		}
@@ -599,6 +875,23 @@ func visitObjectDefinition(ctx context.Context, h *Handler, v model.ObjectDefini
 	return nil
 }
 
+func visitImplementedInterfaces(ctx context.Context, h *Handler, ch chan model.NamedType) error {
+	for typ := range ch {
+		if hfunc := h.EnterImplementedInterface; hfunc != nil {
+			if err := hfunc(ctx, typ); err != nil {
+				return errors.Wrap(err, `failed to visit implemented interface (enter)`)
+			}
+		}
+
+		if hfunc := h.LeaveImplementedInterface; hfunc != nil {
+			if err := hfunc(ctx, typ); err != nil {
+				return errors.Wrap(err, `failed to visit implemented interface (leave)`)
+			}
+		}
+	}
+	return nil
+}
+
 func visitObjectFieldDefinitionList(ctx context.Context, h *Handler, ch chan model.ObjectFieldDefinition) error {
 	if len(ch) == 0 {
 		return nil
@@ -631,6 +924,16 @@ func visitObjectFieldDefinition(ctx context.Context, h *Handler, v model.ObjectF
 		}
 	}
 
+	for arg := range v.Arguments() {
+		if err := visitObjectFieldArgumentDefinition(ctx, h, arg); err != nil {
+			return errors.Wrap(err, `failed to visit object field argument definition`)
+		}
+	}
+
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
 	if hfunc := h.LeaveObjectFieldDefinition; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
 			return errors.Wrap(err, `failed to visit object field definition (leave)`)
@@ -640,6 +943,31 @@ func visitObjectFieldDefinition(ctx context.Context, h *Handler, v model.ObjectF
 	return nil
 }
 
+func visitObjectFieldArgumentDefinition(ctx context.Context, h *Handler, v model.ObjectFieldArgumentDefinition) error {
+	if hfunc := h.EnterObjectFieldArgumentDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit object field argument definition (enter)`)
+		}
+	}
+
+	if v.HasDefaultValue() {
+		if err := visitValue(ctx, h, v.DefaultValue()); err != nil {
+			return errors.Wrap(err, `failed to visit default value`)
+		}
+	}
+
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
+	if hfunc := h.LeaveObjectFieldArgumentDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit object field argument definition (leave)`)
+		}
+	}
+	return nil
+}
+
 func visitInterfaceDefinition(ctx context.Context, h *Handler, v model.InterfaceDefinition) error {
 	var prune bool
 	if hfunc := h.EnterInterfaceDefinition; hfunc != nil {
@@ -653,6 +981,14 @@ func visitInterfaceDefinition(ctx context.Context, h *Handler, v model.Interface
 	}
 
 	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
+		if err := visitImplementedInterfaces(ctx, h, v.Interfaces()); err != nil {
+			return errors.Wrap(err, `failed to visit implemented interfaces`)
+		}
+
 		for field := range v.Fields() {
 			if err := visitInterfaceFieldDefinition(ctx, h, field); err != nil {
 				return errors.Wrap(err, `failed to visit interface field definition`)
@@ -675,6 +1011,16 @@ func visitInterfaceFieldDefinition(ctx context.Context, h *Handler, v model.Inte
 		}
 	}
 
+	for arg := range v.Arguments() {
+		if err := visitObjectFieldArgumentDefinition(ctx, h, arg); err != nil {
+			return errors.Wrap(err, `failed to visit object field argument definition`)
+		}
+	}
+
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
 	if hfunc := h.LeaveInterfaceFieldDefinition; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
 			return errors.Wrap(err, `failed to visit interface field definition (leave)`)
@@ -691,6 +1037,16 @@ func visitEnumDefinition(ctx context.Context, h *Handler, v model.EnumDefinition
 		}
 	}
 
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
+	for e := range v.Elements() {
+		if err := visitEnumElementDefinition(ctx, h, e); err != nil {
+			return errors.Wrap(err, `failed to visit enum element definition`)
+		}
+	}
+
 	if hfunc := h.LeaveEnumDefinition; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
 			return errors.Wrap(err, `failed to visit enum definition (leave)`)
@@ -699,6 +1055,25 @@ func visitEnumDefinition(ctx context.Context, h *Handler, v model.EnumDefinition
 	return nil
 }
 
+func visitEnumElementDefinition(ctx context.Context, h *Handler, v model.EnumElementDefinition) error {
+	if hfunc := h.EnterEnumElementDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit enum element definition (enter)`)
+		}
+	}
+
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
+	if hfunc := h.LeaveEnumElementDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit enum element definition (leave)`)
+		}
+	}
+	return nil
+}
+
 func visitUnionDefinition(ctx context.Context, h *Handler, v model.UnionDefinition) error {
 	if hfunc := h.EnterUnionDefinition; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
@@ -706,6 +1081,10 @@ func visitUnionDefinition(ctx context.Context, h *Handler, v model.UnionDefiniti
 		}
 	}
 
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
 	if hfunc := h.LeaveUnionDefinition; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
 			return errors.Wrap(err, `failed to visit union definition (leave)`)
@@ -714,6 +1093,46 @@ func visitUnionDefinition(ctx context.Context, h *Handler, v model.UnionDefiniti
 	return nil
 }
 
+func visitScalarDefinition(ctx context.Context, h *Handler, v model.ScalarDefinition) error {
+	if hfunc := h.EnterScalarDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit scalar definition (enter)`)
+		}
+	}
+
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
+	if hfunc := h.LeaveScalarDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit scalar definition (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitDirectiveDefinition(ctx context.Context, h *Handler, v model.DirectiveDefinition) error {
+	if hfunc := h.EnterDirectiveDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit directive definition (enter)`)
+		}
+	}
+
+	for arg := range v.Arguments() {
+		if err := visitObjectFieldArgumentDefinition(ctx, h, arg); err != nil {
+			return errors.Wrap(err, `failed to visit object field argument definition`)
+		}
+	}
+
+	if hfunc := h.LeaveDirectiveDefinition; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit directive definition (leave)`)
+		}
+	}
+	return nil
+}
+
 func visitInputDefinition(ctx context.Context, h *Handler, v model.InputDefinition) error {
 	var prune bool
 	if hfunc := h.EnterInputDefinition; hfunc != nil {
@@ -727,6 +1146,10 @@ func visitInputDefinition(ctx context.Context, h *Handler, v model.InputDefiniti
 	}
 
 	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
 		if err := visitInputFieldDefinitionList(ctx, h, v.Fields()); err != nil {
 			return errors.Wrap(err, `failed to visit input field definition list`)
 		}
@@ -760,9 +1183,9 @@ func visitInputFieldDefinitionList(ctx context.Context, h *Handler, ch chan mode
 		}
 	}
 
-	if hfunc := h.EnterInputFieldDefinitionList; hfunc != nil {
+	if hfunc := h.LeaveInputFieldDefinitionList; hfunc != nil {
 		if err := hfunc(ctx); err != nil {
-			return errors.Wrap(err, `failed to visit input field definition list (enter)`)
+			return errors.Wrap(err, `failed to visit input field definition list (leave)`)
 		}
 	}
 	return nil
@@ -775,6 +1198,16 @@ func visitInputFieldDefinition(ctx context.Context, h *Handler, v model.InputFie
 		}
 	}
 
+	if v.HasDefaultValue() {
+		if err := visitValue(ctx, h, v.DefaultValue()); err != nil {
+			return errors.Wrap(err, `failed to visit default value`)
+		}
+	}
+
+	if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+		return errors.Wrap(err, `failed to visit directive list`)
+	}
+
 	if hfunc := h.LeaveInputFieldDefinition; hfunc != nil {
 		if err := hfunc(ctx, v); err != nil {
 			return errors.Wrap(err, `failed to visit input field definition (leave)`)
@@ -783,3 +1216,236 @@ func visitInputFieldDefinition(ctx context.Context, h *Handler, v model.InputFie
 
 	return nil
 }
+
+// visitExtension dispatches to the visit function for the specific kind
+// of extension. Note that every extension satisfies model.ScalarExtension,
+// so it must be checked last
+func visitExtension(ctx context.Context, h *Handler, v model.Extension) error {
+	switch v.(type) {
+	case model.ObjectExtension:
+		return visitObjectExtension(ctx, h, v.(model.ObjectExtension))
+	case model.InterfaceExtension:
+		return visitInterfaceExtension(ctx, h, v.(model.InterfaceExtension))
+	case model.UnionExtension:
+		return visitUnionExtension(ctx, h, v.(model.UnionExtension))
+	case model.EnumExtension:
+		return visitEnumExtension(ctx, h, v.(model.EnumExtension))
+	case model.InputExtension:
+		return visitInputExtension(ctx, h, v.(model.InputExtension))
+	case model.SchemaExtension:
+		return visitSchemaExtension(ctx, h, v.(model.SchemaExtension))
+	case model.ScalarExtension:
+		return visitScalarExtension(ctx, h, v.(model.ScalarExtension))
+	}
+	return errors.Errorf(`unknown extension %T`, v)
+}
+
+func visitObjectExtension(ctx context.Context, h *Handler, v model.ObjectExtension) error {
+	var prune bool
+	if hfunc := h.EnterObjectExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit object extension (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
+		if err := visitImplementedInterfaces(ctx, h, v.Interfaces()); err != nil {
+			return errors.Wrap(err, `failed to visit implemented interfaces`)
+		}
+
+		if err := visitObjectFieldDefinitionList(ctx, h, v.Fields()); err != nil {
+			return errors.Wrap(err, `failed to visit object field definition list`)
+		}
+	}
+
+	if hfunc := h.LeaveObjectExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit object extension (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitInterfaceExtension(ctx context.Context, h *Handler, v model.InterfaceExtension) error {
+	var prune bool
+	if hfunc := h.EnterInterfaceExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit interface extension (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
+		if err := visitImplementedInterfaces(ctx, h, v.Interfaces()); err != nil {
+			return errors.Wrap(err, `failed to visit implemented interfaces`)
+		}
+
+		for field := range v.Fields() {
+			if err := visitInterfaceFieldDefinition(ctx, h, field); err != nil {
+				return errors.Wrap(err, `failed to visit interface field definition`)
+			}
+		}
+	}
+
+	if hfunc := h.LeaveInterfaceExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit interface extension (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitUnionExtension(ctx context.Context, h *Handler, v model.UnionExtension) error {
+	var prune bool
+	if hfunc := h.EnterUnionExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit union extension (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+	}
+
+	if hfunc := h.LeaveUnionExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit union extension (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitEnumExtension(ctx context.Context, h *Handler, v model.EnumExtension) error {
+	var prune bool
+	if hfunc := h.EnterEnumExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit enum extension (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
+		for e := range v.Elements() {
+			if err := visitEnumElementDefinition(ctx, h, e); err != nil {
+				return errors.Wrap(err, `failed to visit enum element definition`)
+			}
+		}
+	}
+
+	if hfunc := h.LeaveEnumExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit enum extension (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitInputExtension(ctx context.Context, h *Handler, v model.InputExtension) error {
+	var prune bool
+	if hfunc := h.EnterInputExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit input extension (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+
+		if err := visitInputFieldDefinitionList(ctx, h, v.Fields()); err != nil {
+			return errors.Wrap(err, `failed to visit input field definition list`)
+		}
+	}
+
+	if hfunc := h.LeaveInputExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit input extension (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitScalarExtension(ctx context.Context, h *Handler, v model.ScalarExtension) error {
+	var prune bool
+	if hfunc := h.EnterScalarExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit scalar extension (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+	}
+
+	if hfunc := h.LeaveScalarExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit scalar extension (leave)`)
+		}
+	}
+	return nil
+}
+
+func visitSchemaExtension(ctx context.Context, h *Handler, v model.SchemaExtension) error {
+	var prune bool
+	if hfunc := h.EnterSchemaExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			if perr, ok := isPruneError(err); ok {
+				prune = perr.Prune()
+			} else {
+				return errors.Wrap(err, `failed to visit schema extension (enter)`)
+			}
+		}
+	}
+
+	if !prune {
+		if err := visitDirectiveList(ctx, h, v.Directives()); err != nil {
+			return errors.Wrap(err, `failed to visit directive list`)
+		}
+	}
+
+	if hfunc := h.LeaveSchemaExtension; hfunc != nil {
+		if err := hfunc(ctx, v); err != nil {
+			return errors.Wrap(err, `failed to visit schema extension (leave)`)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,456 @@
+package visitor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lestrrat/go-graphql/format"
+	"github.com/lestrrat/go-graphql/model"
+	"github.com/lestrrat/go-graphql/parser"
+	"github.com/lestrrat/go-graphql/visitor"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// recorder returns a handler that records the hooks that are called,
+// in order, along with the name (or the value) of the node
+func recorder(events *[]string) *visitor.Handler {
+	record := func(event string) error {
+		*events = append(*events, event)
+		return nil
+	}
+
+	return &visitor.Handler{
+		EnterOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			return record("enter operation " + v.Name())
+		},
+		LeaveOperationDefinition: func(_ context.Context, v model.OperationDefinition) error {
+			return record("leave operation " + v.Name())
+		},
+		EnterVariableDefinitionList: func(_ context.Context) error {
+			return record("enter variable list")
+		},
+		LeaveVariableDefinitionList: func(_ context.Context) error {
+			return record("leave variable list")
+		},
+		EnterVariableDefinition: func(_ context.Context, v model.VariableDefinition) error {
+			return record("enter variable $" + v.Name())
+		},
+		LeaveVariableDefinition: func(_ context.Context, v model.VariableDefinition) error {
+			return record("leave variable $" + v.Name())
+		},
+		EnterFragmentDefinition: func(_ context.Context, v model.FragmentDefinition) error {
+			return record("enter fragment " + v.Name())
+		},
+		LeaveFragmentDefinition: func(_ context.Context, v model.FragmentDefinition) error {
+			return record("leave fragment " + v.Name())
+		},
+		EnterDirectiveList: func(_ context.Context) error {
+			return record("enter directive list")
+		},
+		LeaveDirectiveList: func(_ context.Context) error {
+			return record("leave directive list")
+		},
+		EnterDirective: func(_ context.Context, v model.Directive) error {
+			return record("enter directive @" + v.Name())
+		},
+		LeaveDirective: func(_ context.Context, v model.Directive) error {
+			return record("leave directive @" + v.Name())
+		},
+		EnterArgument: func(_ context.Context, v model.Argument) error {
+			return record("enter argument " + v.Name())
+		},
+		LeaveArgument: func(_ context.Context, v model.Argument) error {
+			return record("leave argument " + v.Name())
+		},
+		EnterValue: func(_ context.Context, v model.Value) error {
+			return record("enter value " + format.Value(v))
+		},
+		LeaveValue: func(_ context.Context, v model.Value) error {
+			return record("leave value " + format.Value(v))
+		},
+		EnterSelectionField: func(_ context.Context, v model.SelectionField) error {
+			return record("enter field " + v.Name())
+		},
+		LeaveSelectionField: func(_ context.Context, v model.SelectionField) error {
+			return record("leave field " + v.Name())
+		},
+		EnterObjectDefinition: func(_ context.Context, v model.ObjectDefinition) error {
+			return record("enter object " + v.Name())
+		},
+		LeaveObjectDefinition: func(_ context.Context, v model.ObjectDefinition) error {
+			return record("leave object " + v.Name())
+		},
+		EnterInterfaceDefinition: func(_ context.Context, v model.InterfaceDefinition) error {
+			return record("enter interface " + v.Name())
+		},
+		LeaveInterfaceDefinition: func(_ context.Context, v model.InterfaceDefinition) error {
+			return record("leave interface " + v.Name())
+		},
+		EnterImplementedInterface: func(_ context.Context, v model.NamedType) error {
+			return record("enter implements " + v.Name())
+		},
+		LeaveImplementedInterface: func(_ context.Context, v model.NamedType) error {
+			return record("leave implements " + v.Name())
+		},
+		EnterObjectFieldDefinition: func(_ context.Context, v model.ObjectFieldDefinition) error {
+			return record("enter field definition " + v.Name())
+		},
+		LeaveObjectFieldDefinition: func(_ context.Context, v model.ObjectFieldDefinition) error {
+			return record("leave field definition " + v.Name())
+		},
+		EnterInterfaceFieldDefinition: func(_ context.Context, v model.InterfaceFieldDefinition) error {
+			return record("enter field definition " + v.Name())
+		},
+		LeaveInterfaceFieldDefinition: func(_ context.Context, v model.InterfaceFieldDefinition) error {
+			return record("leave field definition " + v.Name())
+		},
+		EnterObjectFieldArgumentDefinition: func(_ context.Context, v model.ObjectFieldArgumentDefinition) error {
+			return record("enter argument definition " + v.Name())
+		},
+		LeaveObjectFieldArgumentDefinition: func(_ context.Context, v model.ObjectFieldArgumentDefinition) error {
+			return record("leave argument definition " + v.Name())
+		},
+		EnterInputDefinition: func(_ context.Context, v model.InputDefinition) error {
+			return record("enter input " + v.Name())
+		},
+		LeaveInputDefinition: func(_ context.Context, v model.InputDefinition) error {
+			return record("leave input " + v.Name())
+		},
+		EnterInputFieldDefinitionList: func(_ context.Context) error {
+			return record("enter input field list")
+		},
+		LeaveInputFieldDefinitionList: func(_ context.Context) error {
+			return record("leave input field list")
+		},
+		EnterInputFieldDefinition: func(_ context.Context, v model.InputFieldDefinition) error {
+			return record("enter input field " + v.Name())
+		},
+		LeaveInputFieldDefinition: func(_ context.Context, v model.InputFieldDefinition) error {
+			return record("leave input field " + v.Name())
+		},
+		EnterEnumDefinition: func(_ context.Context, v model.EnumDefinition) error {
+			return record("enter enum " + v.Name())
+		},
+		LeaveEnumDefinition: func(_ context.Context, v model.EnumDefinition) error {
+			return record("leave enum " + v.Name())
+		},
+		EnterEnumElementDefinition: func(_ context.Context, v model.EnumElementDefinition) error {
+			return record("enter enum value " + v.Name())
+		},
+		LeaveEnumElementDefinition: func(_ context.Context, v model.EnumElementDefinition) error {
+			return record("leave enum value " + v.Name())
+		},
+		EnterScalarDefinition: func(_ context.Context, v model.ScalarDefinition) error {
+			return record("enter scalar " + v.Name())
+		},
+		LeaveScalarDefinition: func(_ context.Context, v model.ScalarDefinition) error {
+			return record("leave scalar " + v.Name())
+		},
+		EnterDirectiveDefinition: func(_ context.Context, v model.DirectiveDefinition) error {
+			return record("enter directive definition @" + v.Name())
+		},
+		LeaveDirectiveDefinition: func(_ context.Context, v model.DirectiveDefinition) error {
+			return record("leave directive definition @" + v.Name())
+		},
+	}
+}
+
+// visitRecorded parses `src`, which must contain a single definition,
+// and returns the hooks called while visiting it
+func visitRecorded(t *testing.T, src string) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	doc, err := parser.New().ParseString(ctx, src)
+	if !assert.NoError(t, err, "p.Parse should succeed") {
+		t.FailNow()
+	}
+
+	var events []string
+	if !assert.NoError(t, visitor.Visit(ctx, recorder(&events), doc), "visitor.Visit should succeed") {
+		t.FailNow()
+	}
+	return events
+}
+
+func TestVisitOperationDefinition(t *testing.T) {
+	t.Run("Variables and directives", func(t *testing.T) {
+		events := visitRecorded(t, `query Q($id: ID = 1 @tag, $all: Boolean) @live { hero { name } }`)
+		expected := []string{
+			"enter operation Q",
+			"enter variable list",
+			"enter variable $id",
+			"enter value 1",
+			"leave value 1",
+			"enter directive list",
+			"enter directive @tag",
+			"leave directive @tag",
+			"leave directive list",
+			"leave variable $id",
+			"enter variable $all",
+			"leave variable $all",
+			"leave variable list",
+			"enter directive list",
+			"enter directive @live",
+			"leave directive @live",
+			"leave directive list",
+			"enter field hero",
+			"enter field name",
+			"leave field name",
+			"leave field hero",
+			"leave operation Q",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+
+	t.Run("No variables", func(t *testing.T) {
+		events := visitRecorded(t, `query Q @live(if: true) { hero }`)
+		expected := []string{
+			"enter operation Q",
+			"enter directive list",
+			"enter directive @live",
+			"enter argument if",
+			"enter value true",
+			"leave value true",
+			"leave argument if",
+			"leave directive @live",
+			"leave directive list",
+			"enter field hero",
+			"leave field hero",
+			"leave operation Q",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+}
+
+func TestVisitFragmentDefinition(t *testing.T) {
+	events := visitRecorded(t, `fragment F on Human @cached { name }`)
+	expected := []string{
+		"enter fragment F",
+		"enter directive list",
+		"enter directive @cached",
+		"leave directive @cached",
+		"leave directive list",
+		"enter field name",
+		"leave field name",
+		"leave fragment F",
+	}
+	if !assert.Equal(t, expected, events, "events should match") {
+		return
+	}
+}
+
+func TestVisitListValue(t *testing.T) {
+	events := visitRecorded(t, `query Q { sum(values: [1, [2], {n: 3}]) }`)
+	expected := []string{
+		"enter operation Q",
+		"enter field sum",
+		"enter argument values",
+		"enter value [1, [2], {n: 3}]",
+		"enter value 1",
+		"leave value 1",
+		"enter value [2]",
+		"enter value 2",
+		"leave value 2",
+		"leave value [2]",
+		"enter value {n: 3}",
+		"enter value 3",
+		"leave value 3",
+		"leave value {n: 3}",
+		"leave value [1, [2], {n: 3}]",
+		"leave argument values",
+		"leave field sum",
+		"leave operation Q",
+	}
+	if !assert.Equal(t, expected, events, "events should match") {
+		return
+	}
+}
+
+func TestVisitTypeSystemDefinitions(t *testing.T) {
+	t.Run("Object", func(t *testing.T) {
+		events := visitRecorded(t, `type Droid implements Node & Character @key {
+  id: ID! @external
+  friends(first: Int = 10 @deprecated, ids: [ID] = ["1"]): [Character]
+}`)
+		expected := []string{
+			"enter object Droid",
+			"enter directive list",
+			"enter directive @key",
+			"leave directive @key",
+			"leave directive list",
+			"enter implements Node",
+			"leave implements Node",
+			"enter implements Character",
+			"leave implements Character",
+			"enter field definition id",
+			"enter directive list",
+			"enter directive @external",
+			"leave directive @external",
+			"leave directive list",
+			"leave field definition id",
+			"enter field definition friends",
+			"enter argument definition first",
+			"enter value 10",
+			"leave value 10",
+			"enter directive list",
+			"enter directive @deprecated",
+			"leave directive @deprecated",
+			"leave directive list",
+			"leave argument definition first",
+			"enter argument definition ids",
+			`enter value ["1"]`,
+			`enter value "1"`,
+			`leave value "1"`,
+			`leave value ["1"]`,
+			"leave argument definition ids",
+			"leave field definition friends",
+			"leave object Droid",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+
+	t.Run("Interface", func(t *testing.T) {
+		events := visitRecorded(t, `interface Character implements Node { name: String @deprecated(reason: "Use title") }`)
+		expected := []string{
+			"enter interface Character",
+			"enter implements Node",
+			"leave implements Node",
+			"enter field definition name",
+			"enter directive list",
+			"enter directive @deprecated",
+			"enter argument reason",
+			`enter value "Use title"`,
+			`leave value "Use title"`,
+			"leave argument reason",
+			"leave directive @deprecated",
+			"leave directive list",
+			"leave field definition name",
+			"leave interface Character",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+
+	t.Run("Interface field arguments", func(t *testing.T) {
+		events := visitRecorded(t, `interface Node { children(first: Int = 10 @deprecated): [Node] }`)
+		expected := []string{
+			"enter interface Node",
+			"enter field definition children",
+			"enter argument definition first",
+			"enter value 10",
+			"leave value 10",
+			"enter directive list",
+			"enter directive @deprecated",
+			"leave directive @deprecated",
+			"leave directive list",
+			"leave argument definition first",
+			"leave field definition children",
+			"leave interface Node",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+
+	t.Run("Input", func(t *testing.T) {
+		events := visitRecorded(t, `input Filter @oneOf {
+  limit: Int = 10 @deprecated
+  order: Order
+}`)
+		expected := []string{
+			"enter input Filter",
+			"enter directive list",
+			"enter directive @oneOf",
+			"leave directive @oneOf",
+			"leave directive list",
+			"enter input field list",
+			"enter input field limit",
+			"enter value 10",
+			"leave value 10",
+			"enter directive list",
+			"enter directive @deprecated",
+			"leave directive @deprecated",
+			"leave directive list",
+			"leave input field limit",
+			"enter input field order",
+			"leave input field order",
+			"leave input field list",
+			"leave input Filter",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+
+	t.Run("Enum", func(t *testing.T) {
+		events := visitRecorded(t, `enum Episode @cached { NEWHOPE JEDI @deprecated }`)
+		expected := []string{
+			"enter enum Episode",
+			"enter directive list",
+			"enter directive @cached",
+			"leave directive @cached",
+			"leave directive list",
+			"enter enum value NEWHOPE",
+			"leave enum value NEWHOPE",
+			"enter enum value JEDI",
+			"enter directive list",
+			"enter directive @deprecated",
+			"leave directive @deprecated",
+			"leave directive list",
+			"leave enum value JEDI",
+			"leave enum Episode",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+
+	t.Run("Directive", func(t *testing.T) {
+		events := visitRecorded(t, `directive @auth(role: Role! = ADMIN @deprecated, reason: String) on FIELD_DEFINITION`)
+		expected := []string{
+			"enter directive definition @auth",
+			"enter argument definition role",
+			"enter value ADMIN",
+			"leave value ADMIN",
+			"enter directive list",
+			"enter directive @deprecated",
+			"leave directive @deprecated",
+			"leave directive list",
+			"leave argument definition role",
+			"enter argument definition reason",
+			"leave argument definition reason",
+			"leave directive definition @auth",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+
+	t.Run("Scalar", func(t *testing.T) {
+		events := visitRecorded(t, `scalar Date @specifiedBy(url: "https://tools.ietf.org/html/rfc3339")`)
+		expected := []string{
+			"enter scalar Date",
+			"enter directive list",
+			"enter directive @specifiedBy",
+			"enter argument url",
+			`enter value "https://tools.ietf.org/html/rfc3339"`,
+			`leave value "https://tools.ietf.org/html/rfc3339"`,
+			"leave argument url",
+			"leave directive @specifiedBy",
+			"leave directive list",
+			"leave scalar Date",
+		}
+		if !assert.Equal(t, expected, events, "events should match") {
+			return
+		}
+	})
+}